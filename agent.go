@@ -0,0 +1,103 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+	"github.com/trustpilot/beat-exporter/collector"
+)
+
+// agentProcesses json structure of the /processes endpoint of the Elastic Agent
+type agentProcesses struct {
+	Processes []struct {
+		ID     string `json:"id"`
+		PID    string `json:"pid"`
+		Binary string `json:"binary"`
+	} `json:"processes"`
+}
+
+// agentComponents returns a scan for the components the Elastic Agent at agentURL runs, each scraped
+// through the agent's /processes/<id> proxy and labelled with its component id and unit type
+func agentComponents(client *http.Client, agentURL url.URL, name string, options collector.Options) func() ([]scrapeTarget, error) {
+	return func() ([]scrapeTarget, error) {
+		processes, err := loadAgentProcesses(client, agentURL)
+		if err != nil {
+			return nil, err
+		}
+
+		targets := make([]scrapeTarget, 0, len(processes.Processes))
+
+		for _, process := range processes.Processes {
+			componentURL, err := url.Parse(agentURL.String() + "/processes/" + url.PathEscape(process.ID))
+			if err != nil {
+				return nil, err
+			}
+
+			componentOptions := options
+			// the socket, pid file and data path belong to the agent, the components are only known by pid
+			componentOptions.PID, err = strconv.Atoi(process.PID)
+			if err != nil {
+				log.Warnf("Could not parse pid %q of agent component %s, falling back to the process lookup: %v", process.PID, process.ID, err)
+			}
+			componentOptions.SocketPath = ""
+			componentOptions.PIDFile = ""
+			componentOptions.DataPath = ""
+			componentOptions.FilebeatInputs = nil
+			componentOptions.AgentProxy = true
+
+			targets = append(targets, scrapeTarget{
+				key:      process.ID,
+				revision: process.PID,
+				labels: prometheus.Labels{
+					"component_id": process.ID,
+					"unit_type":    agentUnitType(process.ID),
+				},
+				newCollector: func() (prometheus.Collector, error) {
+					beatInfo, err := loadBeatType(client, *componentURL)
+					if err != nil {
+						return nil, err
+					}
+
+					return collector.NewMainCollector(client, componentURL, name, beatInfo, componentOptions), nil
+				},
+			})
+		}
+
+		return targets, nil
+	}
+}
+
+// agentUnitType returns the input type of a component, whose id is <type>-<output name>
+func agentUnitType(id string) string {
+	if i := strings.LastIndex(id, "-"); i > 0 {
+		return id[:i]
+	}
+
+	return id
+}
+
+func loadAgentProcesses(client *http.Client, agentURL url.URL) (*agentProcesses, error) {
+	response, err := client.Get(agentURL.String() + "/processes")
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("agent URL %q status code: %d", agentURL.String(), response.StatusCode)
+	}
+
+	processes := &agentProcesses{}
+
+	if err := json.NewDecoder(response.Body).Decode(processes); err != nil {
+		return nil, err
+	}
+
+	return processes, nil
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/trustpilot/beat-exporter/collector"
+)
+
+func TestAgentComponents(t *testing.T) {
+	var requested []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.EscapedPath())
+
+		switch r.URL.EscapedPath() {
+		case "/processes":
+			w.Write([]byte(`{"processes": [
+				{"id": "system/metrics-default", "pid": "1234", "binary": "metricbeat"},
+				{"id": "filestream-monitoring", "pid": "", "binary": "filebeat"}
+			]}`))
+		case "/processes/system%2Fmetrics-default":
+			w.Write([]byte(`{"beat": "metricbeat", "version": "8.11.1"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	agentURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targets, err := agentComponents(server.Client(), *agentURL, "beat_exporter", collector.Options{})()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(targets) != 2 {
+		t.Fatalf("got %d targets, want 2", len(targets))
+	}
+
+	expected := []struct {
+		key      string
+		revision string
+		labels   prometheus.Labels
+	}{
+		{"system/metrics-default", "1234", prometheus.Labels{"component_id": "system/metrics-default", "unit_type": "system/metrics"}},
+		{"filestream-monitoring", "", prometheus.Labels{"component_id": "filestream-monitoring", "unit_type": "filestream"}},
+	}
+
+	for i, want := range expected {
+		target := targets[i]
+		if target.key != want.key || target.revision != want.revision || !reflect.DeepEqual(target.labels, want.labels) {
+			t.Errorf("target %d: got %s revision %q labels %v, want %s revision %q labels %v",
+				i, target.key, target.revision, target.labels, want.key, want.revision, want.labels)
+		}
+	}
+
+	// the component id is a single path segment of the proxy url
+	if _, err := targets[0].newCollector(); err != nil {
+		t.Errorf("could not create collector through the escaped component url: %v", err)
+	}
+	if requested[len(requested)-1] != "/processes/system%2Fmetrics-default" {
+		t.Errorf("got request for %s, want /processes/system%%2Fmetrics-default", requested[len(requested)-1])
+	}
+
+	if _, err := targets[1].newCollector(); err == nil {
+		t.Error("expected an error for a component the agent does not proxy")
+	}
+}
+
+func TestAgentComponentsStatus(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	agentURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := agentComponents(server.Client(), *agentURL, "beat_exporter", collector.Options{})(); err == nil {
+		t.Error("expected an error when the agent does not serve /processes")
+	}
+}
+
+func TestAgentUnitType(t *testing.T) {
+	tests := map[string]string{
+		"filestream-default":      "filestream",
+		"system/metrics-default":  "system/metrics",
+		"http/metrics-monitoring": "http/metrics",
+		"beat":                    "beat",
+		"-default":                "-default",
+	}
+
+	for id, want := range tests {
+		if got := agentUnitType(id); got != want {
+			t.Errorf("agentUnitType(%q) = %q, want %q", id, got, want)
+		}
+	}
+}
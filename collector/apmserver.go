@@ -2,19 +2,23 @@ package collector
 
 import (
 	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
 )
 
 //Apmserver json structure
 type Apmserver struct {
-	Acm       Acm       `json:"acm"`
-	Decoder   Decoder   `json:"decoder"`
-	Jaeger    Jaeger    `json:"jaeger"`
-	Processor Processor `json:"processor"`
-	Profile   Profile   `json:"profile"`
-	Root      Root      `json:"root"`
-	Sampling  Sampling  `json:"sampling"`
-	Server    Server    `json:"server"`
-	Sourcemap Sourcemap `json:"sourcemap"`
+	Acm         Acm         `json:"acm"`
+	Agentcfg    Agentcfg    `json:"agentcfg"`
+	Aggregation Aggregation `json:"aggregation"`
+	Decoder     Decoder     `json:"decoder"`
+	Jaeger      Jaeger      `json:"jaeger"`
+	Otlp        Otlp        `json:"otlp"`
+	Processor   Processor   `json:"processor"`
+	Profile     Profile     `json:"profile"`
+	Root        Root        `json:"root"`
+	Sampling    Sampling    `json:"sampling"`
+	Server      Server      `json:"server"`
+	Sourcemap   Sourcemap   `json:"sourcemap"`
 }
 
 type Request struct {
@@ -114,6 +118,8 @@ type Sampling struct {
 	Request             Request  `json:"request"`
 	Response            Response `json:"response"`
 	TransactionsDropped float64  `json:"transactions_dropped"`
+	// only reported by apm-server itself, not the jaeger sampling endpoint
+	Tail SamplingTail `json:"tail"`
 }
 type Grpc struct {
 	Collect  Collect  `json:"collect"`
@@ -205,13 +211,28 @@ type apmserverCollector struct {
 	beatInfo *BeatInfo
 	stats    *Stats
 	metrics  exportedMetrics
+	otlp     otlpMetrics
 }
 
-// NewApmserverCollector constructor
+// NewApmserverCollector constructor, 8.x servers get the collector of their restructured stats
 func NewApmserverCollector(beatInfo *BeatInfo, stats *Stats) prometheus.Collector {
-	return &apmserverCollector{
+	var major, minor int
+
+	// the collector is built for every beat, only apm-server versions pick the stats layout
+	if beatInfo.Beat == "apmserver" {
+		var err error
+		if major, minor, err = beatInfo.versionNumbers(); err != nil {
+			log.Warnf("Could not parse apm-server version, assuming the 7.x stats layout: %v", err)
+		}
+	}
+	if major >= 8 {
+		return newApmserver8Collector(beatInfo, stats)
+	}
+
+	c := &apmserverCollector{
 		beatInfo: beatInfo,
 		stats:    stats,
+		otlp:     newOtlpMetrics(beatInfo),
 		metrics: exportedMetrics{
 			// ACM
 			{
@@ -1583,6 +1604,13 @@ func NewApmserverCollector(beatInfo *BeatInfo, stats *Stats) prometheus.Collecto
 			},
 		},
 	}
+
+	// tail-based sampling and the aggregation overflow counters are only reported from 7.13 on
+	if major == 7 && minor >= 13 {
+		c.metrics = append(c.metrics, apmserverIntakeMetrics(beatInfo)...)
+	}
+
+	return c
 }
 
 // Describe returns all descriptions of the collector.
@@ -1591,6 +1619,7 @@ func (c *apmserverCollector) Describe(ch chan<- *prometheus.Desc) {
 	for _, metric := range c.metrics {
 		ch <- metric.desc
 	}
+	c.otlp.describe(ch)
 
 }
 
@@ -1600,5 +1629,6 @@ func (c *apmserverCollector) Collect(ch chan<- prometheus.Metric) {
 	for _, i := range c.metrics {
 		ch <- prometheus.MustNewConstMetric(i.desc, i.valType, i.eval(c.stats))
 	}
+	c.otlp.collect(ch, c.stats)
 
 }
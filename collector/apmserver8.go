@@ -0,0 +1,377 @@
+package collector
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+//Agentcfg json structure of the 8.x agent configuration cache, replacing the acm fetch counters
+type Agentcfg struct {
+	Elasticsearch struct {
+		Cache struct {
+			Entries struct {
+				Count float64 `json:"count"`
+			} `json:"entries"`
+			Refresh struct {
+				Successes float64 `json:"successes"`
+				Failures  float64 `json:"failures"`
+			} `json:"refresh"`
+		} `json:"cache"`
+		Fetch struct {
+			Es          float64 `json:"es"`
+			Fallback    float64 `json:"fallback"`
+			Invalid     float64 `json:"invalid"`
+			Unavailable float64 `json:"unavailable"`
+		} `json:"fetch"`
+	} `json:"elasticsearch"`
+}
+
+//ApmserverOutput json structure of the 8.x elasticsearch output, reported at the top level
+type ApmserverOutput struct {
+	Elasticsearch struct {
+		BulkRequests struct {
+			Available float64 `json:"available"`
+			Completed float64 `json:"completed"`
+		} `json:"bulk_requests"`
+		Indexers struct {
+			Active    float64 `json:"active"`
+			Created   float64 `json:"created"`
+			Destroyed float64 `json:"destroyed"`
+		} `json:"indexers"`
+	} `json:"elasticsearch"`
+}
+
+type apmserver8Collector struct {
+	beatInfo *BeatInfo
+	stats    *Stats
+	metrics  exportedMetrics
+	otlp     otlpMetrics
+}
+
+// newApmserver8Collector constructor of the apm-server 8.x collector, which dropped the decoder, jaeger,
+// profile and sourcemap sections and the frame and stacktrace counters of the processors
+func newApmserver8Collector(beatInfo *BeatInfo, stats *Stats) prometheus.Collector {
+	metrics := exportedMetrics{
+		{
+			desc: prometheus.NewDesc(
+				prometheus.BuildFQName(beatInfo.Beat, "agentcfg", "cache_entries"),
+				"apm-server.agentcfg.elasticsearch.cache.entries.count",
+				nil, nil,
+			),
+			eval:    func(stats *Stats) float64 { return stats.Apmserver.Agentcfg.Elasticsearch.Cache.Entries.Count },
+			valType: prometheus.GaugeValue,
+		},
+		{
+			desc: prometheus.NewDesc(
+				prometheus.BuildFQName(beatInfo.Beat, "agentcfg", "cache_refreshes_total"),
+				"apm-server.agentcfg.elasticsearch.cache.refresh",
+				nil, prometheus.Labels{"result": "success"},
+			),
+			eval:    func(stats *Stats) float64 { return stats.Apmserver.Agentcfg.Elasticsearch.Cache.Refresh.Successes },
+			valType: prometheus.CounterValue,
+		},
+		{
+			desc: prometheus.NewDesc(
+				prometheus.BuildFQName(beatInfo.Beat, "agentcfg", "cache_refreshes_total"),
+				"apm-server.agentcfg.elasticsearch.cache.refresh",
+				nil, prometheus.Labels{"result": "failure"},
+			),
+			eval:    func(stats *Stats) float64 { return stats.Apmserver.Agentcfg.Elasticsearch.Cache.Refresh.Failures },
+			valType: prometheus.CounterValue,
+		},
+		{
+			desc: prometheus.NewDesc(
+				prometheus.BuildFQName(beatInfo.Beat, "agentcfg", "fetches_total"),
+				"apm-server.agentcfg.elasticsearch.fetch",
+				nil, prometheus.Labels{"result": "es"},
+			),
+			eval:    func(stats *Stats) float64 { return stats.Apmserver.Agentcfg.Elasticsearch.Fetch.Es },
+			valType: prometheus.CounterValue,
+		},
+		{
+			desc: prometheus.NewDesc(
+				prometheus.BuildFQName(beatInfo.Beat, "agentcfg", "fetches_total"),
+				"apm-server.agentcfg.elasticsearch.fetch",
+				nil, prometheus.Labels{"result": "fallback"},
+			),
+			eval:    func(stats *Stats) float64 { return stats.Apmserver.Agentcfg.Elasticsearch.Fetch.Fallback },
+			valType: prometheus.CounterValue,
+		},
+		{
+			desc: prometheus.NewDesc(
+				prometheus.BuildFQName(beatInfo.Beat, "agentcfg", "fetches_total"),
+				"apm-server.agentcfg.elasticsearch.fetch",
+				nil, prometheus.Labels{"result": "invalid"},
+			),
+			eval:    func(stats *Stats) float64 { return stats.Apmserver.Agentcfg.Elasticsearch.Fetch.Invalid },
+			valType: prometheus.CounterValue,
+		},
+		{
+			desc: prometheus.NewDesc(
+				prometheus.BuildFQName(beatInfo.Beat, "agentcfg", "fetches_total"),
+				"apm-server.agentcfg.elasticsearch.fetch",
+				nil, prometheus.Labels{"result": "unavailable"},
+			),
+			eval:    func(stats *Stats) float64 { return stats.Apmserver.Agentcfg.Elasticsearch.Fetch.Unavailable },
+			valType: prometheus.CounterValue,
+		},
+		{
+			desc: prometheus.NewDesc(
+				prometheus.BuildFQName(beatInfo.Beat, "processor", "transformations"),
+				"apm-server.processor.<event>.transformations",
+				nil, prometheus.Labels{"event": "error"},
+			),
+			eval:    func(stats *Stats) float64 { return stats.Apmserver.Processor.Error.Transformations },
+			valType: prometheus.CounterValue,
+		},
+		{
+			desc: prometheus.NewDesc(
+				prometheus.BuildFQName(beatInfo.Beat, "processor", "transformations"),
+				"apm-server.processor.<event>.transformations",
+				nil, prometheus.Labels{"event": "metric"},
+			),
+			eval:    func(stats *Stats) float64 { return stats.Apmserver.Processor.Metric.Transformations },
+			valType: prometheus.CounterValue,
+		},
+		{
+			desc: prometheus.NewDesc(
+				prometheus.BuildFQName(beatInfo.Beat, "processor", "transformations"),
+				"apm-server.processor.<event>.transformations",
+				nil, prometheus.Labels{"event": "span"},
+			),
+			eval:    func(stats *Stats) float64 { return stats.Apmserver.Processor.Span.Transformations },
+			valType: prometheus.CounterValue,
+		},
+		{
+			desc: prometheus.NewDesc(
+				prometheus.BuildFQName(beatInfo.Beat, "processor", "transformations"),
+				"apm-server.processor.<event>.transformations",
+				nil, prometheus.Labels{"event": "transaction"},
+			),
+			eval:    func(stats *Stats) float64 { return stats.Apmserver.Processor.Transaction.Transformations },
+			valType: prometheus.CounterValue,
+		},
+		{
+			desc: prometheus.NewDesc(
+				prometheus.BuildFQName(beatInfo.Beat, "processor", "stream_accepted"),
+				"apm-server.processor.stream.accepted",
+				nil, nil,
+			),
+			eval:    func(stats *Stats) float64 { return stats.Apmserver.Processor.Stream.Accepted },
+			valType: prometheus.CounterValue,
+		},
+		{
+			desc: prometheus.NewDesc(
+				prometheus.BuildFQName(beatInfo.Beat, "processor", "stream_errors"),
+				"apm-server.processor.stream.errors",
+				nil, prometheus.Labels{"error": "invalid"},
+			),
+			eval:    func(stats *Stats) float64 { return stats.Apmserver.Processor.Stream.Errors.Invalid },
+			valType: prometheus.CounterValue,
+		},
+		{
+			desc: prometheus.NewDesc(
+				prometheus.BuildFQName(beatInfo.Beat, "processor", "stream_errors"),
+				"apm-server.processor.stream.errors",
+				nil, prometheus.Labels{"error": "toolarge"},
+			),
+			eval:    func(stats *Stats) float64 { return stats.Apmserver.Processor.Stream.Errors.Toolarge },
+			valType: prometheus.CounterValue,
+		},
+		{
+			desc: prometheus.NewDesc(
+				prometheus.BuildFQName(beatInfo.Beat, "sampling", "transactions_dropped"),
+				"apm-server.sampling.transactions_dropped",
+				nil, nil,
+			),
+			eval:    func(stats *Stats) float64 { return stats.Apmserver.Sampling.TransactionsDropped },
+			valType: prometheus.CounterValue,
+		},
+		{
+			desc: prometheus.NewDesc(
+				prometheus.BuildFQName(beatInfo.Beat, "output", "elasticsearch_bulk_requests"),
+				"output.elasticsearch.bulk_requests",
+				nil, prometheus.Labels{"state": "available"},
+			),
+			eval:    func(stats *Stats) float64 { return stats.Output.Elasticsearch.BulkRequests.Available },
+			valType: prometheus.GaugeValue,
+		},
+		{
+			desc: prometheus.NewDesc(
+				prometheus.BuildFQName(beatInfo.Beat, "output", "elasticsearch_bulk_requests_completed_total"),
+				"output.elasticsearch.bulk_requests.completed",
+				nil, nil,
+			),
+			eval:    func(stats *Stats) float64 { return stats.Output.Elasticsearch.BulkRequests.Completed },
+			valType: prometheus.CounterValue,
+		},
+		{
+			desc: prometheus.NewDesc(
+				prometheus.BuildFQName(beatInfo.Beat, "output", "elasticsearch_indexers"),
+				"output.elasticsearch.indexers.active",
+				nil, nil,
+			),
+			eval:    func(stats *Stats) float64 { return stats.Output.Elasticsearch.Indexers.Active },
+			valType: prometheus.GaugeValue,
+		},
+		{
+			desc: prometheus.NewDesc(
+				prometheus.BuildFQName(beatInfo.Beat, "output", "elasticsearch_indexers_total"),
+				"output.elasticsearch.indexers.created and destroyed",
+				nil, prometheus.Labels{"event": "created"},
+			),
+			eval:    func(stats *Stats) float64 { return stats.Output.Elasticsearch.Indexers.Created },
+			valType: prometheus.CounterValue,
+		},
+		{
+			desc: prometheus.NewDesc(
+				prometheus.BuildFQName(beatInfo.Beat, "output", "elasticsearch_indexers_total"),
+				"output.elasticsearch.indexers.created and destroyed",
+				nil, prometheus.Labels{"event": "destroyed"},
+			),
+			eval:    func(stats *Stats) float64 { return stats.Output.Elasticsearch.Indexers.Destroyed },
+			valType: prometheus.CounterValue,
+		},
+	}
+
+	// the intake and agent configuration endpoints keep the 7.x request and response counters
+	metrics = append(metrics, apmserverEndpointMetrics(beatInfo, "acm", func(stats *Stats) Server { return Server(stats.Apmserver.Acm) })...)
+	metrics = append(metrics, apmserverEndpointMetrics(beatInfo, "root", func(stats *Stats) Server { return Server(stats.Apmserver.Root) })...)
+	metrics = append(metrics, apmserverEndpointMetrics(beatInfo, "server", func(stats *Stats) Server { return stats.Apmserver.Server })...)
+	metrics = append(metrics, apmserverIntakeMetrics(beatInfo)...)
+
+	return &apmserver8Collector{
+		beatInfo: beatInfo,
+		stats:    stats,
+		metrics:  metrics,
+		otlp:     newOtlpMetrics(beatInfo),
+	}
+}
+
+// apmserverEndpointMetrics returns the request and response counters of an endpoint, named as by the 7.x collector
+func apmserverEndpointMetrics(beatInfo *BeatInfo, endpoint string, eval func(stats *Stats) Server) exportedMetrics {
+	help := "apm-server." + endpoint + "."
+
+	metrics := exportedMetrics{
+		{
+			desc: prometheus.NewDesc(
+				prometheus.BuildFQName(beatInfo.Beat, endpoint, "request_count"),
+				help+"request.count",
+				nil, nil,
+			),
+			eval:    func(stats *Stats) float64 { return eval(stats).Request.Count },
+			valType: prometheus.CounterValue,
+		},
+		{
+			desc: prometheus.NewDesc(
+				prometheus.BuildFQName(beatInfo.Beat, endpoint, "response_count"),
+				help+"response.count",
+				nil, nil,
+			),
+			eval:    func(stats *Stats) float64 { return eval(stats).Response.Count },
+			valType: prometheus.CounterValue,
+		},
+		{
+			desc: prometheus.NewDesc(
+				prometheus.BuildFQName(beatInfo.Beat, endpoint, "response_errors_count"),
+				help+"response.errors.count",
+				nil, nil,
+			),
+			eval:    func(stats *Stats) float64 { return eval(stats).Response.Errors.Count },
+			valType: prometheus.CounterValue,
+		},
+		{
+			desc: prometheus.NewDesc(
+				prometheus.BuildFQName(beatInfo.Beat, endpoint, "response_valid_count"),
+				help+"response.valid.count",
+				nil, nil,
+			),
+			eval:    func(stats *Stats) float64 { return eval(stats).Response.Valid.Count },
+			valType: prometheus.CounterValue,
+		},
+		{
+			desc: prometheus.NewDesc(
+				prometheus.BuildFQName(beatInfo.Beat, endpoint, "unset"),
+				help+"unset",
+				nil, nil,
+			),
+			eval:    func(stats *Stats) float64 { return eval(stats).Unset },
+			valType: prometheus.CounterValue,
+		},
+	}
+
+	errors := map[string]func(errors Errors) float64{
+		"closed":       func(errors Errors) float64 { return errors.Closed },
+		"decode":       func(errors Errors) float64 { return errors.Decode },
+		"forbidden":    func(errors Errors) float64 { return errors.Forbidden },
+		"internal":     func(errors Errors) float64 { return errors.Internal },
+		"invalidquery": func(errors Errors) float64 { return errors.Invalidquery },
+		"method":       func(errors Errors) float64 { return errors.Method },
+		"notfound":     func(errors Errors) float64 { return errors.Notfound },
+		"queue":        func(errors Errors) float64 { return errors.Queue },
+		"ratelimit":    func(errors Errors) float64 { return errors.Ratelimit },
+		"toolarge":     func(errors Errors) float64 { return errors.Toolarge },
+		"unauthorized": func(errors Errors) float64 { return errors.Unauthorized },
+		"unavailable":  func(errors Errors) float64 { return errors.Unavailable },
+		"validate":     func(errors Errors) float64 { return errors.Validate },
+	}
+
+	for name, value := range errors {
+		value := value
+
+		metrics = append(metrics, exportedMetrics{
+			{
+				desc: prometheus.NewDesc(
+					prometheus.BuildFQName(beatInfo.Beat, endpoint, "response_errors"),
+					help+"response.errors",
+					nil, prometheus.Labels{"error": name},
+				),
+				eval:    func(stats *Stats) float64 { return value(eval(stats).Response.Errors) },
+				valType: prometheus.CounterValue,
+			},
+		}...)
+	}
+
+	valid := map[string]func(valid Valid) float64{
+		"accepted":    func(valid Valid) float64 { return valid.Accepted },
+		"notmodified": func(valid Valid) float64 { return valid.Notmodified },
+		"ok":          func(valid Valid) float64 { return valid.Ok },
+	}
+
+	for status, value := range valid {
+		value := value
+
+		metrics = append(metrics, exportedMetrics{
+			{
+				desc: prometheus.NewDesc(
+					prometheus.BuildFQName(beatInfo.Beat, endpoint, "response_valid"),
+					help+"response.valid",
+					nil, prometheus.Labels{"status": status},
+				),
+				eval:    func(stats *Stats) float64 { return value(eval(stats).Response.Valid) },
+				valType: prometheus.CounterValue,
+			},
+		}...)
+	}
+
+	return metrics
+}
+
+// Describe returns all descriptions of the collector.
+func (c *apmserver8Collector) Describe(ch chan<- *prometheus.Desc) {
+
+	for _, metric := range c.metrics {
+		ch <- metric.desc
+	}
+	c.otlp.describe(ch)
+
+}
+
+// Collect returns the current state of all metrics of the collector.
+func (c *apmserver8Collector) Collect(ch chan<- prometheus.Metric) {
+
+	for _, i := range c.metrics {
+		ch <- prometheus.MustNewConstMetric(i.desc, i.valType, i.eval(c.stats))
+	}
+	c.otlp.collect(ch, c.stats)
+
+}
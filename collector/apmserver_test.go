@@ -0,0 +1,244 @@
+package collector
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	log "github.com/sirupsen/logrus"
+	logtest "github.com/sirupsen/logrus/hooks/test"
+)
+
+func TestApmserverCollectorVersions(t *testing.T) {
+	tests := []struct {
+		version  string
+		fixture  string
+		expected string
+		names    []string
+	}{
+		{
+			version: "7.17.9",
+			fixture: "testdata/apmserver/stats_7.json",
+			expected: `
+# HELP apmserver_jaeger_grpc_collect_request_count apm-server.jaeger.grpc.collect.request.count
+# TYPE apmserver_jaeger_grpc_collect_request_count counter
+apmserver_jaeger_grpc_collect_request_count 96
+# HELP apmserver_decoder_reader_count apm-server.decoder.reader.count
+# TYPE apmserver_decoder_reader_count counter
+apmserver_decoder_reader_count 702
+# HELP apmserver_processor_stream_accepted apm-server.processor.stream.accepted
+# TYPE apmserver_processor_stream_accepted counter
+apmserver_processor_stream_accepted 47211
+# HELP apmserver_server_request_count apm-server.server.request.count
+# TYPE apmserver_server_request_count counter
+apmserver_server_request_count 702
+`,
+			names: []string{
+				"apmserver_jaeger_grpc_collect_request_count",
+				"apmserver_decoder_reader_count",
+				"apmserver_processor_stream_accepted",
+				"apmserver_server_request_count",
+			},
+		},
+		{
+			version: "8.11.1",
+			fixture: "testdata/apmserver/stats_8.json",
+			expected: `
+# HELP apmserver_agentcfg_cache_entries apm-server.agentcfg.elasticsearch.cache.entries.count
+# TYPE apmserver_agentcfg_cache_entries gauge
+apmserver_agentcfg_cache_entries 7
+# HELP apmserver_agentcfg_fetches_total apm-server.agentcfg.elasticsearch.fetch
+# TYPE apmserver_agentcfg_fetches_total counter
+apmserver_agentcfg_fetches_total{result="es"} 60
+apmserver_agentcfg_fetches_total{result="fallback"} 0
+apmserver_agentcfg_fetches_total{result="invalid"} 0
+apmserver_agentcfg_fetches_total{result="unavailable"} 1
+# HELP apmserver_processor_transformations apm-server.processor.<event>.transformations
+# TYPE apmserver_processor_transformations counter
+apmserver_processor_transformations{event="error"} 58
+apmserver_processor_transformations{event="metric"} 9120
+apmserver_processor_transformations{event="span"} 33781
+apmserver_processor_transformations{event="transaction"} 8063
+# HELP apmserver_output_elasticsearch_bulk_requests_completed_total output.elasticsearch.bulk_requests.completed
+# TYPE apmserver_output_elasticsearch_bulk_requests_completed_total counter
+apmserver_output_elasticsearch_bulk_requests_completed_total 803
+# HELP apmserver_server_request_count apm-server.server.request.count
+# TYPE apmserver_server_request_count counter
+apmserver_server_request_count 812
+`,
+			names: []string{
+				"apmserver_agentcfg_cache_entries",
+				"apmserver_agentcfg_fetches_total",
+				"apmserver_processor_transformations",
+				"apmserver_output_elasticsearch_bulk_requests_completed_total",
+				"apmserver_server_request_count",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.version, func(t *testing.T) {
+			c := NewApmserverCollector(&BeatInfo{Beat: "apmserver", Version: test.version}, loadStats(t, test.fixture))
+
+			if err := testutil.CollectAndCompare(c, strings.NewReader(test.expected), test.names...); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestApmserverCollectorLayout(t *testing.T) {
+	tests := []struct {
+		version string
+		is8     bool
+	}{
+		{"7.17.9", false},
+		{"8.0.0", true},
+		{"8.11.1-SNAPSHOT", true},
+		{"9.0.0", true},
+		{"", false},
+		{"unknown", false},
+	}
+
+	for _, test := range tests {
+		_, is8 := NewApmserverCollector(&BeatInfo{Beat: "apmserver", Version: test.version}, &Stats{}).(*apmserver8Collector)
+		if is8 != test.is8 {
+			t.Errorf("version %q: got 8.x collector %v, want %v", test.version, is8, test.is8)
+		}
+	}
+}
+
+func TestAggregationOverflowUnmarshal(t *testing.T) {
+	tests := []struct {
+		name  string
+		json  string
+		total float64
+	}{
+		{"number before 8.7", `{"overflowed": 42}`, 42},
+		{"object since 8.7", `{"overflowed": {"total": 19, "services": 0, "txn_groups": 7, "per_service_txn_groups": 12}}`, 19},
+		{"missing", `{"active_groups": 3}`, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var groups AggregationGroups
+			if err := json.Unmarshal([]byte(test.json), &groups); err != nil {
+				t.Fatal(err)
+			}
+			if groups.Overflowed.Total != test.total {
+				t.Errorf("got overflowed total %v, want %v", groups.Overflowed.Total, test.total)
+			}
+		})
+	}
+
+	var groups AggregationGroups
+	if err := json.Unmarshal([]byte(`{"overflowed": "many"}`), &groups); err == nil {
+		t.Error("expected an error for an overflow counter that is neither a number nor an object")
+	}
+}
+
+func TestApmserverIntakeMetrics(t *testing.T) {
+	c := NewApmserverCollector(&BeatInfo{Beat: "apmserver", Version: "8.11.1"}, loadStats(t, "testdata/apmserver/stats_intake.json"))
+
+	expected := `
+# HELP apmserver_otlp_requests_total apm-server.otlp.<protocol>.<signal>.request.count
+# TYPE apmserver_otlp_requests_total counter
+apmserver_otlp_requests_total{protocol="grpc",signal="logs"} 14
+apmserver_otlp_requests_total{protocol="grpc",signal="metrics"} 120
+apmserver_otlp_requests_total{protocol="grpc",signal="traces"} 931
+apmserver_otlp_requests_total{protocol="http",signal="traces"} 58
+# HELP apmserver_otlp_responses_total apm-server.otlp.<protocol>.<signal>.response.valid.count and errors.count
+# TYPE apmserver_otlp_responses_total counter
+apmserver_otlp_responses_total{protocol="grpc",result="error",signal="logs"} 0
+apmserver_otlp_responses_total{protocol="grpc",result="error",signal="metrics"} 1
+apmserver_otlp_responses_total{protocol="grpc",result="error",signal="traces"} 2
+apmserver_otlp_responses_total{protocol="grpc",result="valid",signal="logs"} 14
+apmserver_otlp_responses_total{protocol="grpc",result="valid",signal="metrics"} 119
+apmserver_otlp_responses_total{protocol="grpc",result="valid",signal="traces"} 929
+apmserver_otlp_responses_total{protocol="http",result="error",signal="traces"} 3
+apmserver_otlp_responses_total{protocol="http",result="valid",signal="traces"} 55
+# HELP apmserver_otlp_unsupported_dropped_total apm-server.otlp.<protocol>.<signal>.consumer.unsupported_dropped
+# TYPE apmserver_otlp_unsupported_dropped_total counter
+apmserver_otlp_unsupported_dropped_total{protocol="grpc",signal="logs"} 0
+apmserver_otlp_unsupported_dropped_total{protocol="grpc",signal="metrics"} 6
+apmserver_otlp_unsupported_dropped_total{protocol="grpc",signal="traces"} 0
+apmserver_otlp_unsupported_dropped_total{protocol="http",signal="traces"} 0
+# HELP apmserver_sampling_tail_events_total apm-server.sampling.tail.events
+# TYPE apmserver_sampling_tail_events_total counter
+apmserver_sampling_tail_events_total{event="dropped"} 40411
+apmserver_sampling_tail_events_total{event="failed_writes"} 0
+apmserver_sampling_tail_events_total{event="head_unsampled"} 12
+apmserver_sampling_tail_events_total{event="processed"} 51022
+apmserver_sampling_tail_events_total{event="sampled"} 10611
+apmserver_sampling_tail_events_total{event="stored"} 50998
+# HELP apmserver_sampling_tail_storage_bytes apm-server.sampling.tail.storage.lsm_size and value_log_size
+# TYPE apmserver_sampling_tail_storage_bytes gauge
+apmserver_sampling_tail_storage_bytes{storage="lsm"} 18874368
+apmserver_sampling_tail_storage_bytes{storage="value_log"} 134217728
+# HELP apmserver_aggregation_overflowed_total apm-server.aggregation.<aggregation>.overflowed
+# TYPE apmserver_aggregation_overflowed_total counter
+apmserver_aggregation_overflowed_total{aggregation="servicedestinations"} 0
+apmserver_aggregation_overflowed_total{aggregation="txmetrics"} 19
+`
+
+	if err := testutil.CollectAndCompare(c, strings.NewReader(expected),
+		"apmserver_otlp_requests_total",
+		"apmserver_otlp_responses_total",
+		"apmserver_otlp_unsupported_dropped_total",
+		"apmserver_sampling_tail_events_total",
+		"apmserver_sampling_tail_storage_bytes",
+		"apmserver_aggregation_overflowed_total",
+	); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestApmserverIntakeMetricsVersions(t *testing.T) {
+	tests := []struct {
+		version string
+		want    bool
+	}{
+		{"6.8.23", false},
+		{"6.13.0", false},
+		{"7.12.1", false},
+		{"7.13.0", true},
+		{"7.17.9", true},
+		{"8.11.1", true},
+	}
+
+	for _, test := range tests {
+		c := NewApmserverCollector(&BeatInfo{Beat: "apmserver", Version: test.version}, &Stats{})
+
+		ch := make(chan *prometheus.Desc, 512)
+		c.Describe(ch)
+		close(ch)
+
+		got := false
+		for desc := range ch {
+			if strings.Contains(desc.String(), `"apmserver_sampling_tail_events_total"`) {
+				got = true
+			}
+		}
+
+		if got != test.want {
+			t.Errorf("version %s: got tail sampling metrics %v, want %v", test.version, got, test.want)
+		}
+	}
+}
+
+func TestApmserverVersionOnlyParsedForApmserver(t *testing.T) {
+	hook := logtest.NewGlobal()
+	defer log.StandardLogger().ReplaceHooks(make(log.LevelHooks))
+
+	NewApmserverCollector(&BeatInfo{Beat: "filebeat", Version: ""}, &Stats{})
+	if len(hook.AllEntries()) != 0 {
+		t.Errorf("got %d log entries for a filebeat without version, want none", len(hook.AllEntries()))
+	}
+
+	NewApmserverCollector(&BeatInfo{Beat: "apmserver", Version: "unknown"}, &Stats{})
+	if entry := hook.LastEntry(); entry == nil || entry.Level != log.WarnLevel {
+		t.Errorf("got %v, want a warning for an unparsable apm-server version", entry)
+	}
+}
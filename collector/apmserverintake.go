@@ -0,0 +1,237 @@
+package collector
+
+import (
+	"encoding/json"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+//SamplingTail json structure of the tail-based sampling processor
+type SamplingTail struct {
+	DynamicServiceGroups float64 `json:"dynamic_service_groups"`
+	Events               struct {
+		Processed     float64 `json:"processed"`
+		Stored        float64 `json:"stored"`
+		Dropped       float64 `json:"dropped"`
+		Sampled       float64 `json:"sampled"`
+		HeadUnsampled float64 `json:"head_unsampled"`
+		FailedWrites  float64 `json:"failed_writes"`
+	} `json:"events"`
+	Storage struct {
+		LsmSize      float64 `json:"lsm_size"`
+		ValueLogSize float64 `json:"value_log_size"`
+	} `json:"storage"`
+}
+
+//OtlpSignal json structure of the intake counters of an OTLP signal
+type OtlpSignal struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+	Consumer struct {
+		UnsupportedDropped float64 `json:"unsupported_dropped"`
+	} `json:"consumer"`
+}
+
+//Otlp json structure of the OTLP intake, per protocol and signal
+type Otlp struct {
+	Grpc map[string]OtlpSignal `json:"grpc"`
+	HTTP map[string]OtlpSignal `json:"http"`
+}
+
+//AggregationOverflow json structure of an aggregation overflow counter
+type AggregationOverflow struct {
+	Total float64 `json:"total"`
+}
+
+// UnmarshalJSON reads the overflow counter as a number, as reported before the split by limit in 8.7
+func (o *AggregationOverflow) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &o.Total); err == nil {
+		return nil
+	}
+
+	type overflow AggregationOverflow
+	return json.Unmarshal(data, (*overflow)(o))
+}
+
+//AggregationGroups json structure of the groups of a metrics aggregation
+type AggregationGroups struct {
+	ActiveGroups float64             `json:"active_groups"`
+	Overflowed   AggregationOverflow `json:"overflowed"`
+}
+
+//Aggregation json structure
+type Aggregation struct {
+	Txmetrics           AggregationGroups `json:"txmetrics"`
+	Servicedestinations AggregationGroups `json:"servicedestinations"`
+}
+
+// apmserverIntakeMetrics returns the tail-based sampling and aggregation metrics of 7.13 and later servers
+func apmserverIntakeMetrics(beatInfo *BeatInfo) exportedMetrics {
+	return exportedMetrics{
+		{
+			desc: prometheus.NewDesc(
+				prometheus.BuildFQName(beatInfo.Beat, "sampling", "tail_dynamic_service_groups"),
+				"apm-server.sampling.tail.dynamic_service_groups",
+				nil, nil,
+			),
+			eval:    func(stats *Stats) float64 { return stats.Apmserver.Sampling.Tail.DynamicServiceGroups },
+			valType: prometheus.GaugeValue,
+		},
+		{
+			desc: prometheus.NewDesc(
+				prometheus.BuildFQName(beatInfo.Beat, "sampling", "tail_events_total"),
+				"apm-server.sampling.tail.events",
+				nil, prometheus.Labels{"event": "processed"},
+			),
+			eval:    func(stats *Stats) float64 { return stats.Apmserver.Sampling.Tail.Events.Processed },
+			valType: prometheus.CounterValue,
+		},
+		{
+			desc: prometheus.NewDesc(
+				prometheus.BuildFQName(beatInfo.Beat, "sampling", "tail_events_total"),
+				"apm-server.sampling.tail.events",
+				nil, prometheus.Labels{"event": "stored"},
+			),
+			eval:    func(stats *Stats) float64 { return stats.Apmserver.Sampling.Tail.Events.Stored },
+			valType: prometheus.CounterValue,
+		},
+		{
+			desc: prometheus.NewDesc(
+				prometheus.BuildFQName(beatInfo.Beat, "sampling", "tail_events_total"),
+				"apm-server.sampling.tail.events",
+				nil, prometheus.Labels{"event": "dropped"},
+			),
+			eval:    func(stats *Stats) float64 { return stats.Apmserver.Sampling.Tail.Events.Dropped },
+			valType: prometheus.CounterValue,
+		},
+		{
+			desc: prometheus.NewDesc(
+				prometheus.BuildFQName(beatInfo.Beat, "sampling", "tail_events_total"),
+				"apm-server.sampling.tail.events",
+				nil, prometheus.Labels{"event": "sampled"},
+			),
+			eval:    func(stats *Stats) float64 { return stats.Apmserver.Sampling.Tail.Events.Sampled },
+			valType: prometheus.CounterValue,
+		},
+		{
+			desc: prometheus.NewDesc(
+				prometheus.BuildFQName(beatInfo.Beat, "sampling", "tail_events_total"),
+				"apm-server.sampling.tail.events",
+				nil, prometheus.Labels{"event": "head_unsampled"},
+			),
+			eval:    func(stats *Stats) float64 { return stats.Apmserver.Sampling.Tail.Events.HeadUnsampled },
+			valType: prometheus.CounterValue,
+		},
+		{
+			desc: prometheus.NewDesc(
+				prometheus.BuildFQName(beatInfo.Beat, "sampling", "tail_events_total"),
+				"apm-server.sampling.tail.events",
+				nil, prometheus.Labels{"event": "failed_writes"},
+			),
+			eval:    func(stats *Stats) float64 { return stats.Apmserver.Sampling.Tail.Events.FailedWrites },
+			valType: prometheus.CounterValue,
+		},
+		{
+			desc: prometheus.NewDesc(
+				prometheus.BuildFQName(beatInfo.Beat, "sampling", "tail_storage_bytes"),
+				"apm-server.sampling.tail.storage.lsm_size and value_log_size",
+				nil, prometheus.Labels{"storage": "lsm"},
+			),
+			eval:    func(stats *Stats) float64 { return stats.Apmserver.Sampling.Tail.Storage.LsmSize },
+			valType: prometheus.GaugeValue,
+		},
+		{
+			desc: prometheus.NewDesc(
+				prometheus.BuildFQName(beatInfo.Beat, "sampling", "tail_storage_bytes"),
+				"apm-server.sampling.tail.storage.lsm_size and value_log_size",
+				nil, prometheus.Labels{"storage": "value_log"},
+			),
+			eval:    func(stats *Stats) float64 { return stats.Apmserver.Sampling.Tail.Storage.ValueLogSize },
+			valType: prometheus.GaugeValue,
+		},
+		{
+			desc: prometheus.NewDesc(
+				prometheus.BuildFQName(beatInfo.Beat, "aggregation", "active_groups"),
+				"apm-server.aggregation.<aggregation>.active_groups",
+				nil, prometheus.Labels{"aggregation": "txmetrics"},
+			),
+			eval:    func(stats *Stats) float64 { return stats.Apmserver.Aggregation.Txmetrics.ActiveGroups },
+			valType: prometheus.GaugeValue,
+		},
+		{
+			desc: prometheus.NewDesc(
+				prometheus.BuildFQName(beatInfo.Beat, "aggregation", "active_groups"),
+				"apm-server.aggregation.<aggregation>.active_groups",
+				nil, prometheus.Labels{"aggregation": "servicedestinations"},
+			),
+			eval:    func(stats *Stats) float64 { return stats.Apmserver.Aggregation.Servicedestinations.ActiveGroups },
+			valType: prometheus.GaugeValue,
+		},
+		{
+			desc: prometheus.NewDesc(
+				prometheus.BuildFQName(beatInfo.Beat, "aggregation", "overflowed_total"),
+				"apm-server.aggregation.<aggregation>.overflowed",
+				nil, prometheus.Labels{"aggregation": "txmetrics"},
+			),
+			eval:    func(stats *Stats) float64 { return stats.Apmserver.Aggregation.Txmetrics.Overflowed.Total },
+			valType: prometheus.CounterValue,
+		},
+		{
+			desc: prometheus.NewDesc(
+				prometheus.BuildFQName(beatInfo.Beat, "aggregation", "overflowed_total"),
+				"apm-server.aggregation.<aggregation>.overflowed",
+				nil, prometheus.Labels{"aggregation": "servicedestinations"},
+			),
+			eval:    func(stats *Stats) float64 { return stats.Apmserver.Aggregation.Servicedestinations.Overflowed.Total },
+			valType: prometheus.CounterValue,
+		},
+	}
+}
+
+// otlpMetrics exports the OTLP intake counters of the apm-server collectors
+type otlpMetrics struct {
+	requests  *prometheus.Desc
+	responses *prometheus.Desc
+	dropped   *prometheus.Desc
+}
+
+func newOtlpMetrics(beatInfo *BeatInfo) otlpMetrics {
+	return otlpMetrics{
+		requests: prometheus.NewDesc(
+			prometheus.BuildFQName(beatInfo.Beat, "otlp", "requests_total"),
+			"apm-server.otlp.<protocol>.<signal>.request.count",
+			[]string{"protocol", "signal"}, nil,
+		),
+		responses: prometheus.NewDesc(
+			prometheus.BuildFQName(beatInfo.Beat, "otlp", "responses_total"),
+			"apm-server.otlp.<protocol>.<signal>.response.valid.count and errors.count",
+			[]string{"protocol", "signal", "result"}, nil,
+		),
+		// only metrics report events of unsupported types
+		dropped: prometheus.NewDesc(
+			prometheus.BuildFQName(beatInfo.Beat, "otlp", "unsupported_dropped_total"),
+			"apm-server.otlp.<protocol>.<signal>.consumer.unsupported_dropped",
+			[]string{"protocol", "signal"}, nil,
+		),
+	}
+}
+
+func (o otlpMetrics) describe(ch chan<- *prometheus.Desc) {
+	ch <- o.requests
+	ch <- o.responses
+	ch <- o.dropped
+}
+
+func (o otlpMetrics) collect(ch chan<- prometheus.Metric, stats *Stats) {
+	o.collectProtocol(ch, "grpc", stats.Apmserver.Otlp.Grpc)
+	o.collectProtocol(ch, "http", stats.Apmserver.Otlp.HTTP)
+}
+
+func (o otlpMetrics) collectProtocol(ch chan<- prometheus.Metric, protocol string, signals map[string]OtlpSignal) {
+	for signal, counters := range signals {
+		ch <- prometheus.MustNewConstMetric(o.requests, prometheus.CounterValue, counters.Request.Count, protocol, signal)
+		ch <- prometheus.MustNewConstMetric(o.responses, prometheus.CounterValue, counters.Response.Valid.Count, protocol, signal, "valid")
+		ch <- prometheus.MustNewConstMetric(o.responses, prometheus.CounterValue, counters.Response.Errors.Count, protocol, signal, "error")
+		ch <- prometheus.MustNewConstMetric(o.dropped, prometheus.CounterValue, counters.Consumer.UnsupportedDropped, protocol, signal)
+	}
+}
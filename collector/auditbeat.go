@@ -0,0 +1,114 @@
+package collector
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+//FileIntegrity json structure of the file_integrity scanner and hasher
+type FileIntegrity struct {
+	Scanner struct {
+		Files    float64 `json:"files"`
+		Bytes    float64 `json:"bytes"`
+		Duration struct {
+			MS float64 `json:"ms"`
+		} `json:"duration"`
+	} `json:"scanner"`
+	Hasher struct {
+		Files float64 `json:"files"`
+		Bytes float64 `json:"bytes"`
+	} `json:"hasher"`
+}
+
+type auditbeatCollector struct {
+	beatInfo *BeatInfo
+	stats    *Stats
+	metrics  exportedMetrics
+	dataset  *prometheus.Desc
+}
+
+// NewAuditbeatCollector constructor
+func NewAuditbeatCollector(beatInfo *BeatInfo, stats *Stats) prometheus.Collector {
+	return &auditbeatCollector{
+		beatInfo: beatInfo,
+		stats:    stats,
+		metrics: exportedMetrics{
+			{
+				desc: prometheus.NewDesc(
+					prometheus.BuildFQName(beatInfo.Beat, "file_integrity", "scanned_files"),
+					"file_integrity.scanner.files",
+					nil, nil,
+				),
+				eval:    func(stats *Stats) float64 { return stats.FileIntegrity.Scanner.Files },
+				valType: prometheus.GaugeValue,
+			},
+			{
+				desc: prometheus.NewDesc(
+					prometheus.BuildFQName(beatInfo.Beat, "file_integrity", "scanned_bytes"),
+					"file_integrity.scanner.bytes",
+					nil, nil,
+				),
+				eval:    func(stats *Stats) float64 { return stats.FileIntegrity.Scanner.Bytes },
+				valType: prometheus.GaugeValue,
+			},
+			{
+				desc: prometheus.NewDesc(
+					prometheus.BuildFQName(beatInfo.Beat, "file_integrity", "scan_duration_seconds"),
+					"file_integrity.scanner.duration.ms",
+					nil, nil,
+				),
+				eval:    func(stats *Stats) float64 { return stats.FileIntegrity.Scanner.Duration.MS / 1000 },
+				valType: prometheus.GaugeValue,
+			},
+			{
+				desc: prometheus.NewDesc(
+					prometheus.BuildFQName(beatInfo.Beat, "file_integrity", "hashed_files_total"),
+					"file_integrity.hasher.files",
+					nil, nil,
+				),
+				eval:    func(stats *Stats) float64 { return stats.FileIntegrity.Hasher.Files },
+				valType: prometheus.CounterValue,
+			},
+			{
+				desc: prometheus.NewDesc(
+					prometheus.BuildFQName(beatInfo.Beat, "file_integrity", "hashed_bytes_total"),
+					"file_integrity.hasher.bytes",
+					nil, nil,
+				),
+				eval:    func(stats *Stats) float64 { return stats.FileIntegrity.Hasher.Bytes },
+				valType: prometheus.CounterValue,
+			},
+		},
+		dataset: prometheus.NewDesc(
+			prometheus.BuildFQName(beatInfo.Beat, "dataset", "events"),
+			"metricbeat.<module>.<dataset>",
+			[]string{"module", "dataset", "event"}, nil,
+		),
+	}
+}
+
+// Describe returns all descriptions of the collector.
+func (c *auditbeatCollector) Describe(ch chan<- *prometheus.Desc) {
+
+	for _, metric := range c.metrics {
+		ch <- metric.desc
+	}
+	ch <- c.dataset
+
+}
+
+// Collect returns the current state of all metrics of the collector.
+func (c *auditbeatCollector) Collect(ch chan<- prometheus.Metric) {
+
+	for _, i := range c.metrics {
+		ch <- prometheus.MustNewConstMetric(i.desc, i.valType, i.eval(c.stats))
+	}
+
+	// auditbeat runs its modules through the metricbeat module framework, which reports them under metricbeat
+	for module, datasets := range c.stats.Metricbeat {
+		for dataset, event := range datasets {
+			ch <- prometheus.MustNewConstMetric(c.dataset, prometheus.CounterValue, event.Success, module, dataset, "success")
+			ch <- prometheus.MustNewConstMetric(c.dataset, prometheus.CounterValue, event.Failures, module, dataset, "failures")
+		}
+	}
+
+}
@@ -0,0 +1,60 @@
+package collector
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestAuditbeatCollector(t *testing.T) {
+	beatInfo := &BeatInfo{Beat: "auditbeat"}
+	stats := loadStats(t, "testdata/auditbeat/stats.json")
+
+	expected := `
+# HELP auditbeat_file_integrity_scanned_files file_integrity.scanner.files
+# TYPE auditbeat_file_integrity_scanned_files gauge
+auditbeat_file_integrity_scanned_files 8192
+# HELP auditbeat_file_integrity_scanned_bytes file_integrity.scanner.bytes
+# TYPE auditbeat_file_integrity_scanned_bytes gauge
+auditbeat_file_integrity_scanned_bytes 1.073741824e+09
+# HELP auditbeat_file_integrity_scan_duration_seconds file_integrity.scanner.duration.ms
+# TYPE auditbeat_file_integrity_scan_duration_seconds gauge
+auditbeat_file_integrity_scan_duration_seconds 4.25
+# HELP auditbeat_file_integrity_hashed_files_total file_integrity.hasher.files
+# TYPE auditbeat_file_integrity_hashed_files_total counter
+auditbeat_file_integrity_hashed_files_total 5120
+# HELP auditbeat_file_integrity_hashed_bytes_total file_integrity.hasher.bytes
+# TYPE auditbeat_file_integrity_hashed_bytes_total counter
+auditbeat_file_integrity_hashed_bytes_total 7.340032e+08
+# HELP auditbeat_dataset_events metricbeat.<module>.<dataset>
+# TYPE auditbeat_dataset_events counter
+auditbeat_dataset_events{dataset="auditd",event="failures",module="auditd"} 4
+auditbeat_dataset_events{dataset="auditd",event="success",module="auditd"} 48207
+auditbeat_dataset_events{dataset="file",event="failures",module="file_integrity"} 0
+auditbeat_dataset_events{dataset="file",event="success",module="file_integrity"} 689
+`
+
+	if err := testutil.CollectAndCompare(NewAuditbeatCollector(beatInfo, stats), strings.NewReader(expected)); err != nil {
+		t.Fatal(err)
+	}
+
+	expected = `
+# HELP auditbeat_auditd_kernel_lost auditd.kernel_lost
+# TYPE auditbeat_auditd_kernel_lost gauge
+auditbeat_auditd_kernel_lost 3
+# HELP auditbeat_auditd_reassembler_seq_gaps auditd.reassembler_seq_gaps
+# TYPE auditbeat_auditd_reassembler_seq_gaps gauge
+auditbeat_auditd_reassembler_seq_gaps 12
+# HELP auditbeat_auditd_received_msgs auditd.received_msgs
+# TYPE auditbeat_auditd_received_msgs gauge
+auditbeat_auditd_received_msgs 48211
+# HELP auditbeat_auditd_userspace_lost auditd.userspace_lost
+# TYPE auditbeat_auditd_userspace_lost gauge
+auditbeat_auditd_userspace_lost 0
+`
+
+	if err := testutil.CollectAndCompare(NewAuditdCollector(beatInfo, stats), strings.NewReader(expected)); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,91 @@
+//go:build linux
+// +build linux
+
+package collector
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/procfs"
+	log "github.com/sirupsen/logrus"
+)
+
+type deletedFilesCollector struct {
+	beatInfo *BeatInfo
+	process  beatProcess
+	files    *prometheus.Desc
+	bytes    *prometheus.Desc
+}
+
+// NewDeletedFilesCollector constructor
+func NewDeletedFilesCollector(beatInfo *BeatInfo, process beatProcess) prometheus.Collector {
+	return &deletedFilesCollector{
+		beatInfo: beatInfo,
+		process:  process,
+		files: prometheus.NewDesc(
+			prometheus.BuildFQName(beatInfo.Beat, "process", "deleted_open_files"),
+			"deleted files still held open by the beat",
+			nil, nil,
+		),
+		bytes: prometheus.NewDesc(
+			prometheus.BuildFQName(beatInfo.Beat, "process", "deleted_open_files_bytes"),
+			"disk space pinned by deleted files still held open by the beat",
+			nil, nil,
+		),
+	}
+}
+
+// Describe returns all descriptions of the collector.
+func (c *deletedFilesCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.files
+	ch <- c.bytes
+}
+
+// Collect returns the current state of all metrics of the collector.
+func (c *deletedFilesCollector) Collect(ch chan<- prometheus.Metric) {
+
+	pid, err := c.process.pid()
+	if err != nil {
+		log.Errorf("Could not find beat process: %v", err)
+		return
+	}
+
+	proc, err := procfs.NewProc(pid)
+	if err != nil {
+		log.Errorf("Could not open beat process %d: %v", pid, err)
+		return
+	}
+
+	fds, err := proc.FileDescriptors()
+	if err != nil {
+		log.Errorf("Could not list file descriptors of beat process %d: %v", pid, err)
+		return
+	}
+
+	var files, size float64
+
+	for _, fd := range fds {
+		fdPath := filepath.Join(procfs.DefaultMountPoint, strconv.Itoa(pid), "fd", strconv.Itoa(int(fd)))
+
+		target, err := os.Readlink(fdPath)
+		if err != nil || !strings.HasSuffix(target, " (deleted)") {
+			continue
+		}
+
+		// stat through the fd, the path it pointed to is gone
+		fi, err := os.Stat(fdPath)
+		if err != nil || !fi.Mode().IsRegular() {
+			continue
+		}
+
+		files++
+		size += float64(fi.Size())
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.files, prometheus.GaugeValue, files)
+	ch <- prometheus.MustNewConstMetric(c.bytes, prometheus.GaugeValue, size)
+}
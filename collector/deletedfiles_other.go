@@ -0,0 +1,25 @@
+//go:build !linux
+// +build !linux
+
+package collector
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+)
+
+type deletedFilesCollector struct{}
+
+// NewDeletedFilesCollector constructor, deleted files are only detected on linux
+func NewDeletedFilesCollector(beatInfo *BeatInfo, process beatProcess) prometheus.Collector {
+	return &deletedFilesCollector{}
+}
+
+// Describe returns all descriptions of the collector.
+func (c *deletedFilesCollector) Describe(ch chan<- *prometheus.Desc) {
+}
+
+// Collect returns the current state of all metrics of the collector.
+func (c *deletedFilesCollector) Collect(ch chan<- prometheus.Metric) {
+	log.Debug("Deleted files are only detected on linux")
+}
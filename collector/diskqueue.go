@@ -0,0 +1,104 @@
+package collector
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+)
+
+type diskQueueCollector struct {
+	beatInfo  *BeatInfo
+	dataPath  string
+	segments  *prometheus.Desc
+	bytes     *prometheus.Desc
+	oldestAge *prometheus.Desc
+	freeBytes *prometheus.Desc
+}
+
+// NewDiskQueueCollector constructor
+func NewDiskQueueCollector(beatInfo *BeatInfo, dataPath string) prometheus.Collector {
+	return &diskQueueCollector{
+		beatInfo: beatInfo,
+		dataPath: dataPath,
+		segments: prometheus.NewDesc(
+			prometheus.BuildFQName(beatInfo.Beat, "diskqueue", "segments"),
+			"number of disk queue segment files",
+			nil, nil,
+		),
+		bytes: prometheus.NewDesc(
+			prometheus.BuildFQName(beatInfo.Beat, "diskqueue", "bytes"),
+			"total size of disk queue segment files",
+			nil, nil,
+		),
+		oldestAge: prometheus.NewDesc(
+			prometheus.BuildFQName(beatInfo.Beat, "diskqueue", "oldest_segment_age_seconds"),
+			"age of the oldest disk queue segment file",
+			nil, nil,
+		),
+		freeBytes: prometheus.NewDesc(
+			prometheus.BuildFQName(beatInfo.Beat, "diskqueue", "filesystem_free_bytes"),
+			"free space on the filesystem holding the beat data path",
+			nil, nil,
+		),
+	}
+}
+
+// Describe returns all descriptions of the collector.
+func (c *diskQueueCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.segments
+	ch <- c.bytes
+	ch <- c.oldestAge
+	ch <- c.freeBytes
+}
+
+// Collect returns the current state of all metrics of the collector.
+func (c *diskQueueCollector) Collect(ch chan<- prometheus.Metric) {
+
+	var (
+		segments float64
+		size     float64
+		oldest   time.Time
+	)
+
+	// the queue directory only exists once queue.disk has written something
+	files, err := ioutil.ReadDir(filepath.Join(c.dataPath, "diskqueue"))
+	if err != nil && !os.IsNotExist(err) {
+		log.Errorf("Could not read disk queue directory: %v", err)
+		return
+	}
+
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), ".seg") {
+			continue
+		}
+
+		segments++
+		size += float64(f.Size())
+
+		if oldest.IsZero() || f.ModTime().Before(oldest) {
+			oldest = f.ModTime()
+		}
+	}
+
+	var age float64
+	if !oldest.IsZero() {
+		age = time.Since(oldest).Seconds()
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.segments, prometheus.GaugeValue, segments)
+	ch <- prometheus.MustNewConstMetric(c.bytes, prometheus.GaugeValue, size)
+	ch <- prometheus.MustNewConstMetric(c.oldestAge, prometheus.GaugeValue, age)
+
+	free, err := filesystemFreeBytes(c.dataPath)
+	if err != nil {
+		log.Errorf("Could not get free space of beat data path: %v", err)
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.freeBytes, prometheus.GaugeValue, float64(free))
+}
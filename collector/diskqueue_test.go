@@ -0,0 +1,111 @@
+package collector
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestDiskQueueCollector(t *testing.T) {
+	dataPath, err := ioutil.TempDir("", "diskqueue")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dataPath)
+
+	c := NewDiskQueueCollector(&BeatInfo{Beat: "filebeat"}, dataPath)
+
+	// before queue.disk wrote anything there is no queue directory
+	empty := `
+# HELP filebeat_diskqueue_segments number of disk queue segment files
+# TYPE filebeat_diskqueue_segments gauge
+filebeat_diskqueue_segments 0
+# HELP filebeat_diskqueue_bytes total size of disk queue segment files
+# TYPE filebeat_diskqueue_bytes gauge
+filebeat_diskqueue_bytes 0
+# HELP filebeat_diskqueue_oldest_segment_age_seconds age of the oldest disk queue segment file
+# TYPE filebeat_diskqueue_oldest_segment_age_seconds gauge
+filebeat_diskqueue_oldest_segment_age_seconds 0
+`
+
+	if err := testutil.CollectAndCompare(c, strings.NewReader(empty),
+		"filebeat_diskqueue_segments",
+		"filebeat_diskqueue_bytes",
+		"filebeat_diskqueue_oldest_segment_age_seconds",
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	queueDir := filepath.Join(dataPath, "diskqueue")
+	if err := os.MkdirAll(filepath.Join(queueDir, "archive.seg"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string]int{"0.seg": 1024, "1.seg": 2048, "state.dat": 64}
+	for name, size := range files {
+		if err := ioutil.WriteFile(filepath.Join(queueDir, name), make([]byte, size), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	oldest := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(filepath.Join(queueDir, "0.seg"), oldest, oldest); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `
+# HELP filebeat_diskqueue_segments number of disk queue segment files
+# TYPE filebeat_diskqueue_segments gauge
+filebeat_diskqueue_segments 2
+# HELP filebeat_diskqueue_bytes total size of disk queue segment files
+# TYPE filebeat_diskqueue_bytes gauge
+filebeat_diskqueue_bytes 3072
+`
+
+	if err := testutil.CollectAndCompare(c, strings.NewReader(expected),
+		"filebeat_diskqueue_segments",
+		"filebeat_diskqueue_bytes",
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	ch := make(chan prometheus.Metric, 8)
+	c.Collect(ch)
+	close(ch)
+
+	var age, free bool
+	for metric := range ch {
+		desc := metric.Desc().String()
+		switch {
+		case strings.Contains(desc, `"filebeat_diskqueue_oldest_segment_age_seconds"`):
+			age = true
+			if value := testutil.ToFloat64(constCollector{metric}); value < 3600 || value > 3660 {
+				t.Errorf("got oldest segment age %v, want about an hour", value)
+			}
+		case strings.Contains(desc, `"filebeat_diskqueue_filesystem_free_bytes"`):
+			free = true
+			if value := testutil.ToFloat64(constCollector{metric}); value <= 0 {
+				t.Errorf("got free bytes %v, want the free space of the temp dir", value)
+			}
+		}
+	}
+
+	if !age || !free {
+		t.Errorf("got oldest segment age %v and free bytes %v, want both exported", age, free)
+	}
+}
+
+// constCollector collects a single metric
+type constCollector struct {
+	metric prometheus.Metric
+}
+
+func (c constCollector) Describe(ch chan<- *prometheus.Desc) { ch <- c.metric.Desc() }
+
+func (c constCollector) Collect(ch chan<- prometheus.Metric) { ch <- c.metric }
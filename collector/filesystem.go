@@ -0,0 +1,30 @@
+//go:build linux || darwin
+// +build linux darwin
+
+package collector
+
+import (
+	"os"
+	"syscall"
+)
+
+// filesystemFreeBytes returns the space available to unprivileged users on the filesystem holding path
+func filesystemFreeBytes(path string) (uint64, error) {
+	var fs syscall.Statfs_t
+
+	if err := syscall.Statfs(path, &fs); err != nil {
+		return 0, err
+	}
+
+	return fs.Bavail * uint64(fs.Bsize), nil
+}
+
+// fileInode returns the inode of a stat result
+func fileInode(fi os.FileInfo) (uint64, bool) {
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, false
+	}
+
+	return uint64(st.Ino), true
+}
@@ -0,0 +1,31 @@
+//go:build windows
+// +build windows
+
+package collector
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// filesystemFreeBytes returns the space available to the current user on the volume holding path
+func filesystemFreeBytes(path string) (uint64, error) {
+	var free, total, totalFree uint64
+
+	p, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := windows.GetDiskFreeSpaceEx(p, &free, &total, &totalFree); err != nil {
+		return 0, err
+	}
+
+	return free, nil
+}
+
+// fileInode is not available on windows, registry entries are matched by path only
+func fileInode(fi os.FileInfo) (uint64, bool) {
+	return 0, false
+}
@@ -0,0 +1,153 @@
+package collector
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+//FleetServerRoute json structure of the counters of an API route
+type FleetServerRoute struct {
+	Active    float64 `json:"active"`
+	Total     float64 `json:"total"`
+	Success   float64 `json:"success"`
+	Error     float64 `json:"error"`
+	RateLimit float64 `json:"rate_limit"`
+	MaxLimit  float64 `json:"max_limit"`
+	MaxBody   float64 `json:"max_body"`
+	Drop      float64 `json:"drop"`
+}
+
+//FleetServer json structure
+type FleetServer struct {
+	HTTPServer struct {
+		TCPOpen  float64 `json:"tcp_open"`
+		TCPClose float64 `json:"tcp_close"`
+	} `json:"http_server"`
+	Routes map[string]FleetServerRoute `json:"routes"`
+	Cache  struct {
+		Hit  float64 `json:"hit"`
+		Miss float64 `json:"miss"`
+	} `json:"cache"`
+}
+
+type fleetServerCollector struct {
+	beatInfo  *BeatInfo
+	stats     *Stats
+	metrics   exportedMetrics
+	active    *prometheus.Desc
+	requests  *prometheus.Desc
+	responses *prometheus.Desc
+	rejected  *prometheus.Desc
+}
+
+// NewFleetServerCollector constructor
+func NewFleetServerCollector(beatInfo *BeatInfo, stats *Stats) prometheus.Collector {
+	return &fleetServerCollector{
+		beatInfo: beatInfo,
+		stats:    stats,
+		metrics: exportedMetrics{
+			{
+				desc: prometheus.NewDesc(
+					prometheus.BuildFQName(beatInfo.Beat, "http_server", "tcp_connections_total"),
+					"http_server.tcp_open and tcp_close",
+					nil, prometheus.Labels{"event": "open"},
+				),
+				eval:    func(stats *Stats) float64 { return stats.FleetServer.HTTPServer.TCPOpen },
+				valType: prometheus.CounterValue,
+			},
+			{
+				desc: prometheus.NewDesc(
+					prometheus.BuildFQName(beatInfo.Beat, "http_server", "tcp_connections_total"),
+					"http_server.tcp_open and tcp_close",
+					nil, prometheus.Labels{"event": "close"},
+				),
+				eval:    func(stats *Stats) float64 { return stats.FleetServer.HTTPServer.TCPClose },
+				valType: prometheus.CounterValue,
+			},
+			{
+				desc: prometheus.NewDesc(
+					prometheus.BuildFQName(beatInfo.Beat, "http_server", "tcp_connections"),
+					"http_server.tcp_open - http_server.tcp_close",
+					nil, nil,
+				),
+				eval: func(stats *Stats) float64 {
+					return stats.FleetServer.HTTPServer.TCPOpen - stats.FleetServer.HTTPServer.TCPClose
+				},
+				valType: prometheus.GaugeValue,
+			},
+			{
+				desc: prometheus.NewDesc(
+					prometheus.BuildFQName(beatInfo.Beat, "cache", "lookups_total"),
+					"cache.hit and miss",
+					nil, prometheus.Labels{"result": "hit"},
+				),
+				eval:    func(stats *Stats) float64 { return stats.FleetServer.Cache.Hit },
+				valType: prometheus.CounterValue,
+			},
+			{
+				desc: prometheus.NewDesc(
+					prometheus.BuildFQName(beatInfo.Beat, "cache", "lookups_total"),
+					"cache.hit and miss",
+					nil, prometheus.Labels{"result": "miss"},
+				),
+				eval:    func(stats *Stats) float64 { return stats.FleetServer.Cache.Miss },
+				valType: prometheus.CounterValue,
+			},
+		},
+		// for the checkin route, active requests are the agents held in a long poll
+		active: prometheus.NewDesc(
+			prometheus.BuildFQName(beatInfo.Beat, "route", "active_requests"),
+			"routes.<route>.active",
+			[]string{"route"}, nil,
+		),
+		requests: prometheus.NewDesc(
+			prometheus.BuildFQName(beatInfo.Beat, "route", "requests_total"),
+			"routes.<route>.total",
+			[]string{"route"}, nil,
+		),
+		responses: prometheus.NewDesc(
+			prometheus.BuildFQName(beatInfo.Beat, "route", "responses_total"),
+			"routes.<route>.success and error",
+			[]string{"route", "result"}, nil,
+		),
+		rejected: prometheus.NewDesc(
+			prometheus.BuildFQName(beatInfo.Beat, "route", "rejected_requests_total"),
+			"routes.<route>.rate_limit, max_limit, max_body and drop",
+			[]string{"route", "reason"}, nil,
+		),
+	}
+}
+
+// Describe returns all descriptions of the collector.
+func (c *fleetServerCollector) Describe(ch chan<- *prometheus.Desc) {
+
+	for _, metric := range c.metrics {
+		ch <- metric.desc
+	}
+	ch <- c.active
+	ch <- c.requests
+	ch <- c.responses
+	ch <- c.rejected
+
+}
+
+// Collect returns the current state of all metrics of the collector.
+func (c *fleetServerCollector) Collect(ch chan<- prometheus.Metric) {
+
+	for _, i := range c.metrics {
+		ch <- prometheus.MustNewConstMetric(i.desc, i.valType, i.eval(c.stats))
+	}
+
+	for name, route := range c.stats.FleetServer.Routes {
+		ch <- prometheus.MustNewConstMetric(c.active, prometheus.GaugeValue, route.Active, name)
+
+		ch <- prometheus.MustNewConstMetric(c.requests, prometheus.CounterValue, route.Total, name)
+		ch <- prometheus.MustNewConstMetric(c.responses, prometheus.CounterValue, route.Success, name, "success")
+		ch <- prometheus.MustNewConstMetric(c.responses, prometheus.CounterValue, route.Error, name, "error")
+
+		ch <- prometheus.MustNewConstMetric(c.rejected, prometheus.CounterValue, route.RateLimit, name, "rate_limit")
+		ch <- prometheus.MustNewConstMetric(c.rejected, prometheus.CounterValue, route.MaxLimit, name, "max_limit")
+		ch <- prometheus.MustNewConstMetric(c.rejected, prometheus.CounterValue, route.MaxBody, name, "max_body")
+		ch <- prometheus.MustNewConstMetric(c.rejected, prometheus.CounterValue, route.Drop, name, "drop")
+	}
+
+}
@@ -0,0 +1,54 @@
+package collector
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestFleetServerCollector(t *testing.T) {
+	c := NewFleetServerCollector(&BeatInfo{Beat: "fleetserver"}, loadStats(t, "testdata/fleetserver/stats.json"))
+
+	expected := `
+# HELP fleetserver_http_server_tcp_connections_total http_server.tcp_open and tcp_close
+# TYPE fleetserver_http_server_tcp_connections_total counter
+fleetserver_http_server_tcp_connections_total{event="close"} 5980
+fleetserver_http_server_tcp_connections_total{event="open"} 6120
+# HELP fleetserver_http_server_tcp_connections http_server.tcp_open - http_server.tcp_close
+# TYPE fleetserver_http_server_tcp_connections gauge
+fleetserver_http_server_tcp_connections 140
+# HELP fleetserver_cache_lookups_total cache.hit and miss
+# TYPE fleetserver_cache_lookups_total counter
+fleetserver_cache_lookups_total{result="hit"} 18342
+fleetserver_cache_lookups_total{result="miss"} 1204
+# HELP fleetserver_route_active_requests routes.<route>.active
+# TYPE fleetserver_route_active_requests gauge
+fleetserver_route_active_requests{route="checkin"} 132
+fleetserver_route_active_requests{route="enroll"} 0
+# HELP fleetserver_route_requests_total routes.<route>.total
+# TYPE fleetserver_route_requests_total counter
+fleetserver_route_requests_total{route="checkin"} 24973
+fleetserver_route_requests_total{route="enroll"} 152
+# HELP fleetserver_route_responses_total routes.<route>.success and error
+# TYPE fleetserver_route_responses_total counter
+fleetserver_route_responses_total{result="error",route="checkin"} 17
+fleetserver_route_responses_total{result="success",route="checkin"} 24811
+fleetserver_route_responses_total{result="error",route="enroll"} 2
+fleetserver_route_responses_total{result="success",route="enroll"} 140
+# HELP fleetserver_route_rejected_requests_total routes.<route>.rate_limit, max_limit, max_body and drop
+# TYPE fleetserver_route_rejected_requests_total counter
+fleetserver_route_rejected_requests_total{reason="drop",route="checkin"} 0
+fleetserver_route_rejected_requests_total{reason="max_body",route="checkin"} 0
+fleetserver_route_rejected_requests_total{reason="max_limit",route="checkin"} 4
+fleetserver_route_rejected_requests_total{reason="rate_limit",route="checkin"} 9
+fleetserver_route_rejected_requests_total{reason="drop",route="enroll"} 1
+fleetserver_route_rejected_requests_total{reason="max_body",route="enroll"} 3
+fleetserver_route_rejected_requests_total{reason="max_limit",route="enroll"} 0
+fleetserver_route_rejected_requests_total{reason="rate_limit",route="enroll"} 6
+`
+
+	if err := testutil.CollectAndCompare(c, strings.NewReader(expected)); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,66 @@
+package collector
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+//Function json structure of the counters of a deployed function
+type Function struct {
+	Invocations float64 `json:"invocations"`
+	Errors      float64 `json:"errors"`
+	Events      float64 `json:"events"`
+}
+
+//Functionbeat json structure
+type Functionbeat struct {
+	Functions map[string]Function `json:"functions"`
+}
+
+type functionbeatCollector struct {
+	beatInfo    *BeatInfo
+	stats       *Stats
+	invocations *prometheus.Desc
+	errors      *prometheus.Desc
+	events      *prometheus.Desc
+}
+
+// NewFunctionbeatCollector constructor
+func NewFunctionbeatCollector(beatInfo *BeatInfo, stats *Stats) prometheus.Collector {
+	return &functionbeatCollector{
+		beatInfo: beatInfo,
+		stats:    stats,
+		invocations: prometheus.NewDesc(
+			prometheus.BuildFQName(beatInfo.Beat, "function", "invocations"),
+			"functionbeat.functions.<name>.invocations",
+			[]string{"function"}, nil,
+		),
+		errors: prometheus.NewDesc(
+			prometheus.BuildFQName(beatInfo.Beat, "function", "errors"),
+			"functionbeat.functions.<name>.errors",
+			[]string{"function"}, nil,
+		),
+		events: prometheus.NewDesc(
+			prometheus.BuildFQName(beatInfo.Beat, "function", "events"),
+			"functionbeat.functions.<name>.events",
+			[]string{"function"}, nil,
+		),
+	}
+}
+
+// Describe returns all descriptions of the collector.
+func (c *functionbeatCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.invocations
+	ch <- c.errors
+	ch <- c.events
+}
+
+// Collect returns the current state of all metrics of the collector.
+func (c *functionbeatCollector) Collect(ch chan<- prometheus.Metric) {
+
+	for name, function := range c.stats.Functionbeat.Functions {
+		ch <- prometheus.MustNewConstMetric(c.invocations, prometheus.CounterValue, function.Invocations, name)
+		ch <- prometheus.MustNewConstMetric(c.errors, prometheus.CounterValue, function.Errors, name)
+		ch <- prometheus.MustNewConstMetric(c.events, prometheus.CounterValue, function.Events, name)
+	}
+
+}
@@ -0,0 +1,31 @@
+package collector
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestFunctionbeatCollector(t *testing.T) {
+	c := NewFunctionbeatCollector(&BeatInfo{Beat: "functionbeat"}, loadStats(t, "testdata/functionbeat/stats.json"))
+
+	expected := `
+# HELP functionbeat_function_invocations functionbeat.functions.<name>.invocations
+# TYPE functionbeat_function_invocations counter
+functionbeat_function_invocations{function="cloudwatch-logs"} 128
+functionbeat_function_invocations{function="sqs-orders"} 44
+# HELP functionbeat_function_errors functionbeat.functions.<name>.errors
+# TYPE functionbeat_function_errors counter
+functionbeat_function_errors{function="cloudwatch-logs"} 2
+functionbeat_function_errors{function="sqs-orders"} 0
+# HELP functionbeat_function_events functionbeat.functions.<name>.events
+# TYPE functionbeat_function_events counter
+functionbeat_function_events{function="cloudwatch-logs"} 5310
+functionbeat_function_events{function="sqs-orders"} 44
+`
+
+	if err := testutil.CollectAndCompare(c, strings.NewReader(expected)); err != nil {
+		t.Fatal(err)
+	}
+}
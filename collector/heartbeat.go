@@ -0,0 +1,155 @@
+package collector
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+//HeartbeatMonitor json structure of the counters of a monitor type
+type HeartbeatMonitor struct {
+	EndpointStarts float64 `json:"endpoint_starts"`
+	EndpointStops  float64 `json:"endpoint_stops"`
+	MonitorStarts  float64 `json:"monitor_starts"`
+	MonitorStops   float64 `json:"monitor_stops"`
+}
+
+//Heartbeat json structure
+type Heartbeat struct {
+	Browser HeartbeatMonitor `json:"browser"`
+	HTTP    HeartbeatMonitor `json:"http"`
+	ICMP    HeartbeatMonitor `json:"icmp"`
+	TCP     HeartbeatMonitor `json:"tcp"`
+
+	Scheduler struct {
+		Jobs struct {
+			Active         float64 `json:"active"`
+			MissedDeadline float64 `json:"missed_deadline"`
+		} `json:"jobs"`
+		Tasks struct {
+			Active  float64 `json:"active"`
+			Waiting float64 `json:"waiting"`
+		} `json:"tasks"`
+	} `json:"scheduler"`
+}
+
+// heartbeatMonitors maps the monitor type label to its counters
+var heartbeatMonitors = map[string]func(h *Heartbeat) *HeartbeatMonitor{
+	"browser": func(h *Heartbeat) *HeartbeatMonitor { return &h.Browser },
+	"http":    func(h *Heartbeat) *HeartbeatMonitor { return &h.HTTP },
+	"icmp":    func(h *Heartbeat) *HeartbeatMonitor { return &h.ICMP },
+	"tcp":     func(h *Heartbeat) *HeartbeatMonitor { return &h.TCP },
+}
+
+type heartbeatCollector struct {
+	beatInfo *BeatInfo
+	stats    *Stats
+	metrics  exportedMetrics
+}
+
+// NewHeartbeatCollector constructor
+func NewHeartbeatCollector(beatInfo *BeatInfo, stats *Stats) prometheus.Collector {
+	metrics := exportedMetrics{
+		{
+			desc: prometheus.NewDesc(
+				prometheus.BuildFQName(beatInfo.Beat, "scheduler", "jobs_active"),
+				"heartbeat.scheduler.jobs.active",
+				nil, nil,
+			),
+			eval:    func(stats *Stats) float64 { return stats.Heartbeat.Scheduler.Jobs.Active },
+			valType: prometheus.GaugeValue,
+		},
+		{
+			desc: prometheus.NewDesc(
+				prometheus.BuildFQName(beatInfo.Beat, "scheduler", "jobs_missed_deadline"),
+				"heartbeat.scheduler.jobs.missed_deadline",
+				nil, nil,
+			),
+			eval:    func(stats *Stats) float64 { return stats.Heartbeat.Scheduler.Jobs.MissedDeadline },
+			valType: prometheus.CounterValue,
+		},
+		{
+			desc: prometheus.NewDesc(
+				prometheus.BuildFQName(beatInfo.Beat, "scheduler", "tasks"),
+				"heartbeat.scheduler.tasks",
+				nil, prometheus.Labels{"state": "active"},
+			),
+			eval:    func(stats *Stats) float64 { return stats.Heartbeat.Scheduler.Tasks.Active },
+			valType: prometheus.GaugeValue,
+		},
+		{
+			desc: prometheus.NewDesc(
+				prometheus.BuildFQName(beatInfo.Beat, "scheduler", "tasks"),
+				"heartbeat.scheduler.tasks",
+				nil, prometheus.Labels{"state": "waiting"},
+			),
+			eval:    func(stats *Stats) float64 { return stats.Heartbeat.Scheduler.Tasks.Waiting },
+			valType: prometheus.GaugeValue,
+		},
+	}
+
+	for name, monitor := range heartbeatMonitors {
+		monitor := monitor
+
+		metrics = append(metrics, exportedMetrics{
+			{
+				desc: prometheus.NewDesc(
+					prometheus.BuildFQName(beatInfo.Beat, "monitor", "starts"),
+					"heartbeat.<type>.monitor_starts",
+					nil, prometheus.Labels{"type": name},
+				),
+				eval:    func(stats *Stats) float64 { return monitor(&stats.Heartbeat).MonitorStarts },
+				valType: prometheus.CounterValue,
+			},
+			{
+				desc: prometheus.NewDesc(
+					prometheus.BuildFQName(beatInfo.Beat, "monitor", "stops"),
+					"heartbeat.<type>.monitor_stops",
+					nil, prometheus.Labels{"type": name},
+				),
+				eval:    func(stats *Stats) float64 { return monitor(&stats.Heartbeat).MonitorStops },
+				valType: prometheus.CounterValue,
+			},
+			{
+				desc: prometheus.NewDesc(
+					prometheus.BuildFQName(beatInfo.Beat, "monitor", "endpoint_starts"),
+					"heartbeat.<type>.endpoint_starts",
+					nil, prometheus.Labels{"type": name},
+				),
+				eval:    func(stats *Stats) float64 { return monitor(&stats.Heartbeat).EndpointStarts },
+				valType: prometheus.CounterValue,
+			},
+			{
+				desc: prometheus.NewDesc(
+					prometheus.BuildFQName(beatInfo.Beat, "monitor", "endpoint_stops"),
+					"heartbeat.<type>.endpoint_stops",
+					nil, prometheus.Labels{"type": name},
+				),
+				eval:    func(stats *Stats) float64 { return monitor(&stats.Heartbeat).EndpointStops },
+				valType: prometheus.CounterValue,
+			},
+		}...)
+	}
+
+	return &heartbeatCollector{
+		beatInfo: beatInfo,
+		stats:    stats,
+		metrics:  metrics,
+	}
+}
+
+// Describe returns all descriptions of the collector.
+func (c *heartbeatCollector) Describe(ch chan<- *prometheus.Desc) {
+
+	for _, metric := range c.metrics {
+		ch <- metric.desc
+	}
+
+}
+
+// Collect returns the current state of all metrics of the collector.
+func (c *heartbeatCollector) Collect(ch chan<- prometheus.Metric) {
+
+	for _, i := range c.metrics {
+		ch <- prometheus.MustNewConstMetric(i.desc, i.valType, i.eval(c.stats))
+	}
+
+}
@@ -0,0 +1,53 @@
+package collector
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestHeartbeatCollector(t *testing.T) {
+	c := NewHeartbeatCollector(&BeatInfo{Beat: "heartbeat"}, loadStats(t, "testdata/heartbeat/stats.json"))
+
+	expected := `
+# HELP heartbeat_scheduler_jobs_active heartbeat.scheduler.jobs.active
+# TYPE heartbeat_scheduler_jobs_active gauge
+heartbeat_scheduler_jobs_active 4
+# HELP heartbeat_scheduler_jobs_missed_deadline heartbeat.scheduler.jobs.missed_deadline
+# TYPE heartbeat_scheduler_jobs_missed_deadline counter
+heartbeat_scheduler_jobs_missed_deadline 7
+# HELP heartbeat_scheduler_tasks heartbeat.scheduler.tasks
+# TYPE heartbeat_scheduler_tasks gauge
+heartbeat_scheduler_tasks{state="active"} 2
+heartbeat_scheduler_tasks{state="waiting"} 1
+# HELP heartbeat_monitor_starts heartbeat.<type>.monitor_starts
+# TYPE heartbeat_monitor_starts counter
+heartbeat_monitor_starts{type="browser"} 0
+heartbeat_monitor_starts{type="http"} 3
+heartbeat_monitor_starts{type="icmp"} 1
+heartbeat_monitor_starts{type="tcp"} 2
+# HELP heartbeat_monitor_stops heartbeat.<type>.monitor_stops
+# TYPE heartbeat_monitor_stops counter
+heartbeat_monitor_stops{type="browser"} 0
+heartbeat_monitor_stops{type="http"} 1
+heartbeat_monitor_stops{type="icmp"} 0
+heartbeat_monitor_stops{type="tcp"} 2
+# HELP heartbeat_monitor_endpoint_starts heartbeat.<type>.endpoint_starts
+# TYPE heartbeat_monitor_endpoint_starts counter
+heartbeat_monitor_endpoint_starts{type="browser"} 0
+heartbeat_monitor_endpoint_starts{type="http"} 6
+heartbeat_monitor_endpoint_starts{type="icmp"} 1
+heartbeat_monitor_endpoint_starts{type="tcp"} 2
+# HELP heartbeat_monitor_endpoint_stops heartbeat.<type>.endpoint_stops
+# TYPE heartbeat_monitor_endpoint_stops counter
+heartbeat_monitor_endpoint_stops{type="browser"} 0
+heartbeat_monitor_endpoint_stops{type="http"} 2
+heartbeat_monitor_endpoint_stops{type="icmp"} 0
+heartbeat_monitor_endpoint_stops{type="tcp"} 2
+`
+
+	if err := testutil.CollectAndCompare(c, strings.NewReader(expected)); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,42 @@
+package collector
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+//Histogram json structure of a beat histogram snapshot
+type Histogram struct {
+	Count  float64 `json:"count"`
+	Max    float64 `json:"max"`
+	Mean   float64 `json:"mean"`
+	Median float64 `json:"median"`
+	Min    float64 `json:"min"`
+	P75    float64 `json:"p75"`
+	P95    float64 `json:"p95"`
+	P99    float64 `json:"p99"`
+	P999   float64 `json:"p999"`
+	StdDev float64 `json:"stddev"`
+}
+
+type exportedHistograms []struct {
+	desc *prometheus.Desc
+	eval func(stats *Stats) Histogram
+}
+
+// newHistogramSummary converts a beat histogram snapshot into a prometheus summary.
+// Beats only keep a sample of observations, so the sum is derived from the sample mean.
+func newHistogramSummary(desc *prometheus.Desc, h Histogram, labelValues ...string) prometheus.Metric {
+	return prometheus.MustNewConstSummary(
+		desc,
+		uint64(h.Count),
+		h.Mean*h.Count,
+		map[float64]float64{
+			0.5:   h.Median,
+			0.75:  h.P75,
+			0.95:  h.P95,
+			0.99:  h.P99,
+			0.999: h.P999,
+		},
+		labelValues...,
+	)
+}
@@ -0,0 +1,190 @@
+package collector
+
+import (
+	"net/http"
+	"net/url"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+)
+
+//Input json structure of an entry of the /inputs/ endpoint
+type Input struct {
+	ID    string `json:"id"`
+	Input string `json:"input"`
+
+	BytesProcessedTotal   float64 `json:"bytes_processed_total"`
+	EventsProcessedTotal  float64 `json:"events_processed_total"`
+	ProcessingErrorsTotal float64 `json:"processing_errors_total"`
+	ReceivedBytesTotal    float64 `json:"received_bytes_total"`
+	ReceivedEventsTotal   float64 `json:"received_events_total"`
+	ProcessingTime        struct {
+		Histogram Histogram `json:"histogram"`
+	} `json:"processing_time"`
+
+	FilestreamInput
+	AWSS3Input
+	HTTPJSONInput
+	AFPacketInput
+	WinlogInput
+}
+
+type exportedInputMetrics []struct {
+	desc    *prometheus.Desc
+	eval    func(input *Input) float64
+	valType prometheus.ValueType
+}
+
+type exportedInputHistograms []struct {
+	desc *prometheus.Desc
+	eval func(input *Input) Histogram
+}
+
+// inputMetricSet holds the metrics exported for one kind of input, labels returns the values
+// of the labels its descriptions have in addition to id and type
+type inputMetricSet struct {
+	metrics    exportedInputMetrics
+	histograms exportedInputHistograms
+	labels     func(input *Input) []string
+}
+
+type inputsCollector struct {
+	beatInfo *BeatInfo
+	client   *http.Client
+	beatURL  *url.URL
+	common   inputMetricSet
+	types    map[string]inputMetricSet
+	notFound sync.Once
+}
+
+// newInputDesc creates a description labelled with the input id and type, followed by any extra labels
+func newInputDesc(beatInfo *BeatInfo, name string, help string, labels ...string) *prometheus.Desc {
+	return prometheus.NewDesc(
+		prometheus.BuildFQName(beatInfo.Beat, "input", name),
+		help,
+		append([]string{"id", "type"}, labels...), nil,
+	)
+}
+
+// NewInputsCollector constructor
+func NewInputsCollector(beatInfo *BeatInfo, client *http.Client, url *url.URL) prometheus.Collector {
+	return &inputsCollector{
+		beatInfo: beatInfo,
+		client:   client,
+		beatURL:  url,
+		common: inputMetricSet{
+			metrics: exportedInputMetrics{
+				{
+					desc:    newInputDesc(beatInfo, "bytes_processed_total", "inputs.bytes_processed_total"),
+					eval:    func(input *Input) float64 { return input.BytesProcessedTotal },
+					valType: prometheus.CounterValue,
+				},
+				{
+					desc:    newInputDesc(beatInfo, "events_processed_total", "inputs.events_processed_total"),
+					eval:    func(input *Input) float64 { return input.EventsProcessedTotal },
+					valType: prometheus.CounterValue,
+				},
+				{
+					desc:    newInputDesc(beatInfo, "processing_errors_total", "inputs.processing_errors_total"),
+					eval:    func(input *Input) float64 { return input.ProcessingErrorsTotal },
+					valType: prometheus.CounterValue,
+				},
+				{
+					desc:    newInputDesc(beatInfo, "received_bytes_total", "inputs.received_bytes_total"),
+					eval:    func(input *Input) float64 { return input.ReceivedBytesTotal },
+					valType: prometheus.CounterValue,
+				},
+				{
+					desc:    newInputDesc(beatInfo, "received_events_total", "inputs.received_events_total"),
+					eval:    func(input *Input) float64 { return input.ReceivedEventsTotal },
+					valType: prometheus.CounterValue,
+				},
+			},
+			histograms: exportedInputHistograms{
+				{
+					desc: newInputDesc(beatInfo, "processing_time_nanoseconds", "inputs.processing_time"),
+					eval: func(input *Input) Histogram { return input.ProcessingTime.Histogram },
+				},
+			},
+		},
+		types: newInputTypeMetricSets(beatInfo),
+	}
+}
+
+// Describe returns all descriptions of the collector.
+func (c *inputsCollector) Describe(ch chan<- *prometheus.Desc) {
+
+	c.common.describe(ch)
+
+	for _, set := range c.types {
+		set.describe(ch)
+	}
+
+}
+
+// Collect returns the current state of all metrics of the collector.
+func (c *inputsCollector) Collect(ch chan<- prometheus.Metric) {
+
+	var inputs []Input
+
+	err := fetchJSON(c.client, c.beatURL.String()+"/inputs/", &inputs)
+	if err == errNotFound {
+		// beats before 7.16 have no /inputs/ endpoint
+		c.notFound.Do(func() {
+			log.Debugf("Target %v has no /inputs/ endpoint, not exporting input metrics", c.beatURL.String())
+		})
+		return
+	}
+	if err != nil {
+		log.Errorf("Failed getting /inputs/ endpoint of target: " + err.Error())
+		return
+	}
+
+	seen := make(map[[2]string]bool, len(inputs))
+
+	// series are built from the current response only, so removed inputs disappear on the next scrape
+	for i := range inputs {
+		input := &inputs[i]
+
+		// unnamed inputs may share an empty or duplicate id, their series would collide and fail the whole scrape
+		key := [2]string{input.ID, input.Input}
+		if seen[key] {
+			log.Debugf("Skipping input with duplicate id %q of type %s", input.ID, input.Input)
+			continue
+		}
+		seen[key] = true
+
+		c.common.collect(ch, input)
+
+		if set, ok := c.types[input.Input]; ok {
+			set.collect(ch, input)
+		}
+	}
+
+}
+
+func (s inputMetricSet) describe(ch chan<- *prometheus.Desc) {
+	for _, metric := range s.metrics {
+		ch <- metric.desc
+	}
+
+	for _, histogram := range s.histograms {
+		ch <- histogram.desc
+	}
+}
+
+func (s inputMetricSet) collect(ch chan<- prometheus.Metric, input *Input) {
+	labelValues := []string{input.ID, input.Input}
+	if s.labels != nil {
+		labelValues = append(labelValues, s.labels(input)...)
+	}
+
+	for _, i := range s.metrics {
+		ch <- prometheus.MustNewConstMetric(i.desc, i.valType, i.eval(input), labelValues...)
+	}
+
+	for _, i := range s.histograms {
+		ch <- newHistogramSummary(i.desc, i.eval(input), labelValues...)
+	}
+}
@@ -0,0 +1,95 @@
+package collector
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestWinlogInputs(t *testing.T) {
+	server, beatURL := serveFixtures(t, map[string]string{
+		"/inputs/": "testdata/winlogbeat/inputs.json",
+	})
+	defer server.Close()
+
+	c := NewInputsCollector(&BeatInfo{Beat: "winlogbeat"}, server.Client(), beatURL)
+
+	expected := `
+# HELP winlogbeat_input_received_events_total inputs.received_events_total
+# TYPE winlogbeat_input_received_events_total counter
+winlogbeat_input_received_events_total{id="Application",type="winlog"} 1401
+winlogbeat_input_received_events_total{id="Security",type="winlog"} 7243
+# HELP winlogbeat_input_discarded_events_total inputs.discarded_events_total
+# TYPE winlogbeat_input_discarded_events_total counter
+winlogbeat_input_discarded_events_total{channel="Application",id="Application",type="winlog"} 0
+winlogbeat_input_discarded_events_total{channel="Security",id="Security",type="winlog"} 3
+# HELP winlogbeat_input_errors_total inputs.errors_total
+# TYPE winlogbeat_input_errors_total counter
+winlogbeat_input_errors_total{channel="Application",id="Application",type="winlog"} 0
+winlogbeat_input_errors_total{channel="Security",id="Security",type="winlog"} 1
+# HELP winlogbeat_input_batch_read_period_nanoseconds inputs.batch_read_period
+# TYPE winlogbeat_input_batch_read_period_nanoseconds summary
+winlogbeat_input_batch_read_period_nanoseconds{channel="Application",id="Application",type="winlog",quantile="0.5"} 1000377500
+winlogbeat_input_batch_read_period_nanoseconds{channel="Application",id="Application",type="winlog",quantile="0.75"} 1001562300
+winlogbeat_input_batch_read_period_nanoseconds{channel="Application",id="Application",type="winlog",quantile="0.95"} 1011021850
+winlogbeat_input_batch_read_period_nanoseconds{channel="Application",id="Application",type="winlog",quantile="0.99"} 5000943200
+winlogbeat_input_batch_read_period_nanoseconds{channel="Application",id="Application",type="winlog",quantile="0.999"} 5003418100
+winlogbeat_input_batch_read_period_nanoseconds_sum{channel="Application",id="Application",type="winlog"} 416961970410
+winlogbeat_input_batch_read_period_nanoseconds_count{channel="Application",id="Application",type="winlog"} 412
+winlogbeat_input_batch_read_period_nanoseconds{channel="Security",id="Security",type="winlog",quantile="0.5"} 1000412600
+winlogbeat_input_batch_read_period_nanoseconds{channel="Security",id="Security",type="winlog",quantile="0.75"} 1002011900
+winlogbeat_input_batch_read_period_nanoseconds{channel="Security",id="Security",type="winlog",quantile="0.95"} 1100341200
+winlogbeat_input_batch_read_period_nanoseconds{channel="Security",id="Security",type="winlog",quantile="0.99"} 5000522100
+winlogbeat_input_batch_read_period_nanoseconds{channel="Security",id="Security",type="winlog",quantile="0.999"} 5001007600
+winlogbeat_input_batch_read_period_nanoseconds_sum{channel="Security",id="Security",type="winlog"} 410558102557
+winlogbeat_input_batch_read_period_nanoseconds_count{channel="Security",id="Security",type="winlog"} 398
+`
+
+	if err := testutil.CollectAndCompare(c, strings.NewReader(expected),
+		"winlogbeat_input_received_events_total",
+		"winlogbeat_input_discarded_events_total",
+		"winlogbeat_input_errors_total",
+		"winlogbeat_input_batch_read_period_nanoseconds",
+	); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestInputsDuplicateIDs(t *testing.T) {
+	server, beatURL := serveFixtures(t, map[string]string{
+		"/inputs/": "testdata/filebeat/inputs_duplicate_ids.json",
+	})
+	defer server.Close()
+
+	registry := prometheus.NewPedanticRegistry()
+	registry.MustRegister(NewInputsCollector(&BeatInfo{Beat: "filebeat"}, server.Client(), beatURL))
+
+	expected := `
+# HELP filebeat_input_bytes_processed_total inputs.bytes_processed_total
+# TYPE filebeat_input_bytes_processed_total counter
+filebeat_input_bytes_processed_total{id="",type="log"} 100
+filebeat_input_bytes_processed_total{id="nginx-access",type="filestream"} 4096
+`
+
+	if err := testutil.GatherAndCompare(registry, strings.NewReader(expected), "filebeat_input_bytes_processed_total"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestInputsNotFound(t *testing.T) {
+	server, beatURL := serveFixtures(t, map[string]string{})
+	defer server.Close()
+
+	registry := prometheus.NewPedanticRegistry()
+	registry.MustRegister(NewInputsCollector(&BeatInfo{Beat: "filebeat"}, server.Client(), beatURL))
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(families) != 0 {
+		t.Fatalf("expected no input metrics without an /inputs/ endpoint, got %d families", len(families))
+	}
+}
@@ -0,0 +1,303 @@
+package collector
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+//FilestreamInput json structure of filestream specific input metrics
+type FilestreamInput struct {
+	FilesActive            float64 `json:"files_active"`
+	FilesClosedTotal       float64 `json:"files_closed_total"`
+	FilesOpenedTotal       float64 `json:"files_opened_total"`
+	MessagesReadTotal      float64 `json:"messages_read_total"`
+	MessagesTruncatedTotal float64 `json:"messages_truncated_total"`
+}
+
+//AWSS3Input json structure of aws-s3 specific input metrics
+type AWSS3Input struct {
+	S3BytesProcessedTotal               float64 `json:"s3_bytes_processed_total"`
+	S3EventsCreatedTotal                float64 `json:"s3_events_created_total"`
+	S3ObjectsAckedTotal                 float64 `json:"s3_objects_acked_total"`
+	S3ObjectsInflightGauge              float64 `json:"s3_objects_inflight_gauge"`
+	S3ObjectsRequestedTotal             float64 `json:"s3_objects_requested_total"`
+	SQSMessagesDeletedTotal             float64 `json:"sqs_messages_deleted_total"`
+	SQSMessagesInflightGauge            float64 `json:"sqs_messages_inflight_gauge"`
+	SQSMessagesReceivedTotal            float64 `json:"sqs_messages_received_total"`
+	SQSMessagesReturnedTotal            float64 `json:"sqs_messages_returned_total"`
+	SQSVisibilityTimeoutExtensionsTotal float64 `json:"sqs_visibility_timeout_extensions_total"`
+	S3ObjectProcessingTime              struct {
+		Histogram Histogram `json:"histogram"`
+	} `json:"s3_object_processing_time"`
+	SQSLagTime struct {
+		Histogram Histogram `json:"histogram"`
+	} `json:"sqs_lag_time"`
+	SQSMessageProcessingTime struct {
+		Histogram Histogram `json:"histogram"`
+	} `json:"sqs_message_processing_time"`
+}
+
+//HTTPJSONInput json structure of httpjson specific input metrics
+type HTTPJSONInput struct {
+	HTTPRequestErrorsTotal      float64 `json:"http_request_errors_total"`
+	HTTPRequestTotal            float64 `json:"http_request_total"`
+	HTTPResponse1xxTotal        float64 `json:"http_response_1xx_total"`
+	HTTPResponse2xxTotal        float64 `json:"http_response_2xx_total"`
+	HTTPResponse3xxTotal        float64 `json:"http_response_3xx_total"`
+	HTTPResponse4xxTotal        float64 `json:"http_response_4xx_total"`
+	HTTPResponse5xxTotal        float64 `json:"http_response_5xx_total"`
+	HTTPResponseBodyBytesTotal  float64 `json:"http_response_body_bytes_total"`
+	HTTPResponseErrorsTotal     float64 `json:"http_response_errors_total"`
+	HTTPResponseTotal           float64 `json:"http_response_total"`
+	HTTPJSONIntervalErrorsTotal float64 `json:"httpjson_interval_errors_total"`
+	HTTPJSONIntervalTotal       float64 `json:"httpjson_interval_total"`
+	HTTPRoundTripTime           struct {
+		Histogram Histogram `json:"histogram"`
+	} `json:"http_round_trip_time"`
+	HTTPJSONIntervalExecutionTime struct {
+		Histogram Histogram `json:"histogram"`
+	} `json:"httpjson_interval_execution_time"`
+}
+
+//AFPacketInput json structure of the packetbeat af_packet sniffer metrics of an interface
+type AFPacketInput struct {
+	Device               string  `json:"device"`
+	PacketsReceivedTotal float64 `json:"packets_received_total"`
+	PacketsDroppedTotal  float64 `json:"packets_dropped_total"`
+}
+
+//WinlogInput json structure of the winlogbeat metrics of an event log channel
+type WinlogInput struct {
+	Provider             string  `json:"provider"`
+	DiscardedEventsTotal float64 `json:"discarded_events_total"`
+	ErrorsTotal          float64 `json:"errors_total"`
+	BatchReadPeriod      struct {
+		Histogram Histogram `json:"histogram"`
+	} `json:"batch_read_period"`
+	ReceivedEventsCount struct {
+		Histogram Histogram `json:"histogram"`
+	} `json:"received_events_count"`
+	SourceLagTime struct {
+		Histogram Histogram `json:"histogram"`
+	} `json:"source_lag_time"`
+}
+
+// newInputTypeMetricSets returns the metrics exported in addition to the common ones, keyed by input type
+func newInputTypeMetricSets(beatInfo *BeatInfo) map[string]inputMetricSet {
+	return map[string]inputMetricSet{
+		"filestream": {
+			metrics: exportedInputMetrics{
+				{
+					desc:    newInputDesc(beatInfo, "files_active", "inputs.files_active"),
+					eval:    func(input *Input) float64 { return input.FilesActive },
+					valType: prometheus.GaugeValue,
+				},
+				{
+					desc:    newInputDesc(beatInfo, "files_closed_total", "inputs.files_closed_total"),
+					eval:    func(input *Input) float64 { return input.FilesClosedTotal },
+					valType: prometheus.CounterValue,
+				},
+				{
+					desc:    newInputDesc(beatInfo, "files_opened_total", "inputs.files_opened_total"),
+					eval:    func(input *Input) float64 { return input.FilesOpenedTotal },
+					valType: prometheus.CounterValue,
+				},
+				{
+					desc:    newInputDesc(beatInfo, "messages_read_total", "inputs.messages_read_total"),
+					eval:    func(input *Input) float64 { return input.MessagesReadTotal },
+					valType: prometheus.CounterValue,
+				},
+				{
+					desc:    newInputDesc(beatInfo, "messages_truncated_total", "inputs.messages_truncated_total"),
+					eval:    func(input *Input) float64 { return input.MessagesTruncatedTotal },
+					valType: prometheus.CounterValue,
+				},
+			},
+		},
+		"aws-s3": {
+			metrics: exportedInputMetrics{
+				{
+					desc:    newInputDesc(beatInfo, "s3_bytes_processed_total", "inputs.s3_bytes_processed_total"),
+					eval:    func(input *Input) float64 { return input.S3BytesProcessedTotal },
+					valType: prometheus.CounterValue,
+				},
+				{
+					desc:    newInputDesc(beatInfo, "s3_events_created_total", "inputs.s3_events_created_total"),
+					eval:    func(input *Input) float64 { return input.S3EventsCreatedTotal },
+					valType: prometheus.CounterValue,
+				},
+				{
+					desc:    newInputDesc(beatInfo, "s3_objects_acked_total", "inputs.s3_objects_acked_total"),
+					eval:    func(input *Input) float64 { return input.S3ObjectsAckedTotal },
+					valType: prometheus.CounterValue,
+				},
+				{
+					desc:    newInputDesc(beatInfo, "s3_objects_inflight", "inputs.s3_objects_inflight_gauge"),
+					eval:    func(input *Input) float64 { return input.S3ObjectsInflightGauge },
+					valType: prometheus.GaugeValue,
+				},
+				{
+					desc:    newInputDesc(beatInfo, "s3_objects_requested_total", "inputs.s3_objects_requested_total"),
+					eval:    func(input *Input) float64 { return input.S3ObjectsRequestedTotal },
+					valType: prometheus.CounterValue,
+				},
+				{
+					desc:    newInputDesc(beatInfo, "sqs_messages_deleted_total", "inputs.sqs_messages_deleted_total"),
+					eval:    func(input *Input) float64 { return input.SQSMessagesDeletedTotal },
+					valType: prometheus.CounterValue,
+				},
+				{
+					desc:    newInputDesc(beatInfo, "sqs_messages_inflight", "inputs.sqs_messages_inflight_gauge"),
+					eval:    func(input *Input) float64 { return input.SQSMessagesInflightGauge },
+					valType: prometheus.GaugeValue,
+				},
+				{
+					desc:    newInputDesc(beatInfo, "sqs_messages_received_total", "inputs.sqs_messages_received_total"),
+					eval:    func(input *Input) float64 { return input.SQSMessagesReceivedTotal },
+					valType: prometheus.CounterValue,
+				},
+				{
+					desc:    newInputDesc(beatInfo, "sqs_messages_returned_total", "inputs.sqs_messages_returned_total"),
+					eval:    func(input *Input) float64 { return input.SQSMessagesReturnedTotal },
+					valType: prometheus.CounterValue,
+				},
+				{
+					desc:    newInputDesc(beatInfo, "sqs_visibility_timeout_extensions_total", "inputs.sqs_visibility_timeout_extensions_total"),
+					eval:    func(input *Input) float64 { return input.SQSVisibilityTimeoutExtensionsTotal },
+					valType: prometheus.CounterValue,
+				},
+			},
+			histograms: exportedInputHistograms{
+				{
+					desc: newInputDesc(beatInfo, "s3_object_processing_time_nanoseconds", "inputs.s3_object_processing_time"),
+					eval: func(input *Input) Histogram { return input.S3ObjectProcessingTime.Histogram },
+				},
+				{
+					desc: newInputDesc(beatInfo, "sqs_lag_time_nanoseconds", "inputs.sqs_lag_time"),
+					eval: func(input *Input) Histogram { return input.SQSLagTime.Histogram },
+				},
+				{
+					desc: newInputDesc(beatInfo, "sqs_message_processing_time_nanoseconds", "inputs.sqs_message_processing_time"),
+					eval: func(input *Input) Histogram { return input.SQSMessageProcessingTime.Histogram },
+				},
+			},
+		},
+		"httpjson": {
+			metrics: exportedInputMetrics{
+				{
+					desc:    newInputDesc(beatInfo, "http_request_errors_total", "inputs.http_request_errors_total"),
+					eval:    func(input *Input) float64 { return input.HTTPRequestErrorsTotal },
+					valType: prometheus.CounterValue,
+				},
+				{
+					desc:    newInputDesc(beatInfo, "http_request_total", "inputs.http_request_total"),
+					eval:    func(input *Input) float64 { return input.HTTPRequestTotal },
+					valType: prometheus.CounterValue,
+				},
+				{
+					desc:    newInputDesc(beatInfo, "http_response_1xx_total", "inputs.http_response_1xx_total"),
+					eval:    func(input *Input) float64 { return input.HTTPResponse1xxTotal },
+					valType: prometheus.CounterValue,
+				},
+				{
+					desc:    newInputDesc(beatInfo, "http_response_2xx_total", "inputs.http_response_2xx_total"),
+					eval:    func(input *Input) float64 { return input.HTTPResponse2xxTotal },
+					valType: prometheus.CounterValue,
+				},
+				{
+					desc:    newInputDesc(beatInfo, "http_response_3xx_total", "inputs.http_response_3xx_total"),
+					eval:    func(input *Input) float64 { return input.HTTPResponse3xxTotal },
+					valType: prometheus.CounterValue,
+				},
+				{
+					desc:    newInputDesc(beatInfo, "http_response_4xx_total", "inputs.http_response_4xx_total"),
+					eval:    func(input *Input) float64 { return input.HTTPResponse4xxTotal },
+					valType: prometheus.CounterValue,
+				},
+				{
+					desc:    newInputDesc(beatInfo, "http_response_5xx_total", "inputs.http_response_5xx_total"),
+					eval:    func(input *Input) float64 { return input.HTTPResponse5xxTotal },
+					valType: prometheus.CounterValue,
+				},
+				{
+					desc:    newInputDesc(beatInfo, "http_response_body_bytes_total", "inputs.http_response_body_bytes_total"),
+					eval:    func(input *Input) float64 { return input.HTTPResponseBodyBytesTotal },
+					valType: prometheus.CounterValue,
+				},
+				{
+					desc:    newInputDesc(beatInfo, "http_response_errors_total", "inputs.http_response_errors_total"),
+					eval:    func(input *Input) float64 { return input.HTTPResponseErrorsTotal },
+					valType: prometheus.CounterValue,
+				},
+				{
+					desc:    newInputDesc(beatInfo, "http_response_total", "inputs.http_response_total"),
+					eval:    func(input *Input) float64 { return input.HTTPResponseTotal },
+					valType: prometheus.CounterValue,
+				},
+				{
+					desc:    newInputDesc(beatInfo, "httpjson_interval_errors_total", "inputs.httpjson_interval_errors_total"),
+					eval:    func(input *Input) float64 { return input.HTTPJSONIntervalErrorsTotal },
+					valType: prometheus.CounterValue,
+				},
+				{
+					desc:    newInputDesc(beatInfo, "httpjson_interval_total", "inputs.httpjson_interval_total"),
+					eval:    func(input *Input) float64 { return input.HTTPJSONIntervalTotal },
+					valType: prometheus.CounterValue,
+				},
+			},
+			histograms: exportedInputHistograms{
+				{
+					desc: newInputDesc(beatInfo, "http_round_trip_time_nanoseconds", "inputs.http_round_trip_time"),
+					eval: func(input *Input) Histogram { return input.HTTPRoundTripTime.Histogram },
+				},
+				{
+					desc: newInputDesc(beatInfo, "httpjson_interval_execution_time_nanoseconds", "inputs.httpjson_interval_execution_time"),
+					eval: func(input *Input) Histogram { return input.HTTPJSONIntervalExecutionTime.Histogram },
+				},
+			},
+		},
+		"af_packet": {
+			metrics: exportedInputMetrics{
+				{
+					desc:    newInputDesc(beatInfo, "packets_received_total", "inputs.packets_received_total", "device"),
+					eval:    func(input *Input) float64 { return input.PacketsReceivedTotal },
+					valType: prometheus.CounterValue,
+				},
+				{
+					desc:    newInputDesc(beatInfo, "packets_dropped_total", "inputs.packets_dropped_total", "device"),
+					eval:    func(input *Input) float64 { return input.PacketsDroppedTotal },
+					valType: prometheus.CounterValue,
+				},
+			},
+			labels: func(input *Input) []string { return []string{input.Device} },
+		},
+		"winlog": {
+			metrics: exportedInputMetrics{
+				{
+					desc:    newInputDesc(beatInfo, "discarded_events_total", "inputs.discarded_events_total", "channel"),
+					eval:    func(input *Input) float64 { return input.DiscardedEventsTotal },
+					valType: prometheus.CounterValue,
+				},
+				{
+					desc:    newInputDesc(beatInfo, "errors_total", "inputs.errors_total", "channel"),
+					eval:    func(input *Input) float64 { return input.ErrorsTotal },
+					valType: prometheus.CounterValue,
+				},
+			},
+			histograms: exportedInputHistograms{
+				{
+					desc: newInputDesc(beatInfo, "batch_read_period_nanoseconds", "inputs.batch_read_period", "channel"),
+					eval: func(input *Input) Histogram { return input.BatchReadPeriod.Histogram },
+				},
+				{
+					desc: newInputDesc(beatInfo, "received_events_count", "inputs.received_events_count", "channel"),
+					eval: func(input *Input) Histogram { return input.ReceivedEventsCount.Histogram },
+				},
+				{
+					desc: newInputDesc(beatInfo, "source_lag_time_nanoseconds", "inputs.source_lag_time", "channel"),
+					eval: func(input *Input) Histogram { return input.SourceLagTime.Histogram },
+				},
+			},
+			labels: func(input *Input) []string { return []string{input.Provider} },
+		},
+	}
+}
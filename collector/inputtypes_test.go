@@ -0,0 +1,178 @@
+package collector
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestInputTypes(t *testing.T) {
+	server, beatURL := serveFixtures(t, map[string]string{
+		"/inputs/": "testdata/filebeat/inputs.json",
+	})
+	defer server.Close()
+
+	c := NewInputsCollector(&BeatInfo{Beat: "filebeat"}, server.Client(), beatURL)
+
+	expected := `
+# HELP filebeat_input_files_active inputs.files_active
+# TYPE filebeat_input_files_active gauge
+filebeat_input_files_active{id="filestream-nginx",type="filestream"} 3
+# HELP filebeat_input_files_closed_total inputs.files_closed_total
+# TYPE filebeat_input_files_closed_total counter
+filebeat_input_files_closed_total{id="filestream-nginx",type="filestream"} 12
+# HELP filebeat_input_files_opened_total inputs.files_opened_total
+# TYPE filebeat_input_files_opened_total counter
+filebeat_input_files_opened_total{id="filestream-nginx",type="filestream"} 15
+# HELP filebeat_input_messages_read_total inputs.messages_read_total
+# TYPE filebeat_input_messages_read_total counter
+filebeat_input_messages_read_total{id="filestream-nginx",type="filestream"} 8120
+# HELP filebeat_input_messages_truncated_total inputs.messages_truncated_total
+# TYPE filebeat_input_messages_truncated_total counter
+filebeat_input_messages_truncated_total{id="filestream-nginx",type="filestream"} 2
+# HELP filebeat_input_s3_bytes_processed_total inputs.s3_bytes_processed_total
+# TYPE filebeat_input_s3_bytes_processed_total counter
+filebeat_input_s3_bytes_processed_total{id="aws-s3-cloudtrail",type="aws-s3"} 7340032
+# HELP filebeat_input_s3_events_created_total inputs.s3_events_created_total
+# TYPE filebeat_input_s3_events_created_total counter
+filebeat_input_s3_events_created_total{id="aws-s3-cloudtrail",type="aws-s3"} 5012
+# HELP filebeat_input_s3_objects_acked_total inputs.s3_objects_acked_total
+# TYPE filebeat_input_s3_objects_acked_total counter
+filebeat_input_s3_objects_acked_total{id="aws-s3-cloudtrail",type="aws-s3"} 188
+# HELP filebeat_input_s3_objects_inflight inputs.s3_objects_inflight_gauge
+# TYPE filebeat_input_s3_objects_inflight gauge
+filebeat_input_s3_objects_inflight{id="aws-s3-cloudtrail",type="aws-s3"} 2
+# HELP filebeat_input_s3_objects_requested_total inputs.s3_objects_requested_total
+# TYPE filebeat_input_s3_objects_requested_total counter
+filebeat_input_s3_objects_requested_total{id="aws-s3-cloudtrail",type="aws-s3"} 190
+# HELP filebeat_input_sqs_messages_deleted_total inputs.sqs_messages_deleted_total
+# TYPE filebeat_input_sqs_messages_deleted_total counter
+filebeat_input_sqs_messages_deleted_total{id="aws-s3-cloudtrail",type="aws-s3"} 186
+# HELP filebeat_input_sqs_messages_inflight inputs.sqs_messages_inflight_gauge
+# TYPE filebeat_input_sqs_messages_inflight gauge
+filebeat_input_sqs_messages_inflight{id="aws-s3-cloudtrail",type="aws-s3"} 4
+# HELP filebeat_input_sqs_messages_received_total inputs.sqs_messages_received_total
+# TYPE filebeat_input_sqs_messages_received_total counter
+filebeat_input_sqs_messages_received_total{id="aws-s3-cloudtrail",type="aws-s3"} 192
+# HELP filebeat_input_sqs_messages_returned_total inputs.sqs_messages_returned_total
+# TYPE filebeat_input_sqs_messages_returned_total counter
+filebeat_input_sqs_messages_returned_total{id="aws-s3-cloudtrail",type="aws-s3"} 1
+# HELP filebeat_input_sqs_visibility_timeout_extensions_total inputs.sqs_visibility_timeout_extensions_total
+# TYPE filebeat_input_sqs_visibility_timeout_extensions_total counter
+filebeat_input_sqs_visibility_timeout_extensions_total{id="aws-s3-cloudtrail",type="aws-s3"} 7
+# HELP filebeat_input_http_request_errors_total inputs.http_request_errors_total
+# TYPE filebeat_input_http_request_errors_total counter
+filebeat_input_http_request_errors_total{id="httpjson-okta",type="httpjson"} 1
+# HELP filebeat_input_http_request_total inputs.http_request_total
+# TYPE filebeat_input_http_request_total counter
+filebeat_input_http_request_total{id="httpjson-okta",type="httpjson"} 96
+# HELP filebeat_input_http_response_1xx_total inputs.http_response_1xx_total
+# TYPE filebeat_input_http_response_1xx_total counter
+filebeat_input_http_response_1xx_total{id="httpjson-okta",type="httpjson"} 0
+# HELP filebeat_input_http_response_2xx_total inputs.http_response_2xx_total
+# TYPE filebeat_input_http_response_2xx_total counter
+filebeat_input_http_response_2xx_total{id="httpjson-okta",type="httpjson"} 93
+# HELP filebeat_input_http_response_3xx_total inputs.http_response_3xx_total
+# TYPE filebeat_input_http_response_3xx_total counter
+filebeat_input_http_response_3xx_total{id="httpjson-okta",type="httpjson"} 0
+# HELP filebeat_input_http_response_4xx_total inputs.http_response_4xx_total
+# TYPE filebeat_input_http_response_4xx_total counter
+filebeat_input_http_response_4xx_total{id="httpjson-okta",type="httpjson"} 2
+# HELP filebeat_input_http_response_5xx_total inputs.http_response_5xx_total
+# TYPE filebeat_input_http_response_5xx_total counter
+filebeat_input_http_response_5xx_total{id="httpjson-okta",type="httpjson"} 0
+# HELP filebeat_input_http_response_body_bytes_total inputs.http_response_body_bytes_total
+# TYPE filebeat_input_http_response_body_bytes_total counter
+filebeat_input_http_response_body_bytes_total{id="httpjson-okta",type="httpjson"} 524288
+# HELP filebeat_input_http_response_errors_total inputs.http_response_errors_total
+# TYPE filebeat_input_http_response_errors_total counter
+filebeat_input_http_response_errors_total{id="httpjson-okta",type="httpjson"} 2
+# HELP filebeat_input_http_response_total inputs.http_response_total
+# TYPE filebeat_input_http_response_total counter
+filebeat_input_http_response_total{id="httpjson-okta",type="httpjson"} 95
+# HELP filebeat_input_httpjson_interval_errors_total inputs.httpjson_interval_errors_total
+# TYPE filebeat_input_httpjson_interval_errors_total counter
+filebeat_input_httpjson_interval_errors_total{id="httpjson-okta",type="httpjson"} 1
+# HELP filebeat_input_httpjson_interval_total inputs.httpjson_interval_total
+# TYPE filebeat_input_httpjson_interval_total counter
+filebeat_input_httpjson_interval_total{id="httpjson-okta",type="httpjson"} 48
+# HELP filebeat_input_s3_object_processing_time_nanoseconds inputs.s3_object_processing_time
+# TYPE filebeat_input_s3_object_processing_time_nanoseconds summary
+filebeat_input_s3_object_processing_time_nanoseconds{id="aws-s3-cloudtrail",type="aws-s3",quantile="0.5"} 1.8e+08
+filebeat_input_s3_object_processing_time_nanoseconds{id="aws-s3-cloudtrail",type="aws-s3",quantile="0.75"} 2.6e+08
+filebeat_input_s3_object_processing_time_nanoseconds{id="aws-s3-cloudtrail",type="aws-s3",quantile="0.95"} 6.1e+08
+filebeat_input_s3_object_processing_time_nanoseconds{id="aws-s3-cloudtrail",type="aws-s3",quantile="0.99"} 9e+08
+filebeat_input_s3_object_processing_time_nanoseconds{id="aws-s3-cloudtrail",type="aws-s3",quantile="0.999"} 9.8e+08
+filebeat_input_s3_object_processing_time_nanoseconds_sum{id="aws-s3-cloudtrail",type="aws-s3"} 3.948e+10
+filebeat_input_s3_object_processing_time_nanoseconds_count{id="aws-s3-cloudtrail",type="aws-s3"} 188
+# HELP filebeat_input_http_round_trip_time_nanoseconds inputs.http_round_trip_time
+# TYPE filebeat_input_http_round_trip_time_nanoseconds summary
+filebeat_input_http_round_trip_time_nanoseconds{id="httpjson-okta",type="httpjson",quantile="0.5"} 1e+08
+filebeat_input_http_round_trip_time_nanoseconds{id="httpjson-okta",type="httpjson",quantile="0.75"} 1.4e+08
+filebeat_input_http_round_trip_time_nanoseconds{id="httpjson-okta",type="httpjson",quantile="0.95"} 3e+08
+filebeat_input_http_round_trip_time_nanoseconds{id="httpjson-okta",type="httpjson",quantile="0.99"} 7e+08
+filebeat_input_http_round_trip_time_nanoseconds{id="httpjson-okta",type="httpjson",quantile="0.999"} 8.1e+08
+filebeat_input_http_round_trip_time_nanoseconds_sum{id="httpjson-okta",type="httpjson"} 1.14e+10
+filebeat_input_http_round_trip_time_nanoseconds_count{id="httpjson-okta",type="httpjson"} 95
+`
+
+	if err := testutil.CollectAndCompare(c, strings.NewReader(expected),
+		"filebeat_input_files_active",
+		"filebeat_input_files_closed_total",
+		"filebeat_input_files_opened_total",
+		"filebeat_input_messages_read_total",
+		"filebeat_input_messages_truncated_total",
+		"filebeat_input_s3_bytes_processed_total",
+		"filebeat_input_s3_events_created_total",
+		"filebeat_input_s3_objects_acked_total",
+		"filebeat_input_s3_objects_inflight",
+		"filebeat_input_s3_objects_requested_total",
+		"filebeat_input_sqs_messages_deleted_total",
+		"filebeat_input_sqs_messages_inflight",
+		"filebeat_input_sqs_messages_received_total",
+		"filebeat_input_sqs_messages_returned_total",
+		"filebeat_input_sqs_visibility_timeout_extensions_total",
+		"filebeat_input_http_request_errors_total",
+		"filebeat_input_http_request_total",
+		"filebeat_input_http_response_1xx_total",
+		"filebeat_input_http_response_2xx_total",
+		"filebeat_input_http_response_3xx_total",
+		"filebeat_input_http_response_4xx_total",
+		"filebeat_input_http_response_5xx_total",
+		"filebeat_input_http_response_body_bytes_total",
+		"filebeat_input_http_response_errors_total",
+		"filebeat_input_http_response_total",
+		"filebeat_input_httpjson_interval_errors_total",
+		"filebeat_input_httpjson_interval_total",
+		"filebeat_input_s3_object_processing_time_nanoseconds",
+		"filebeat_input_http_round_trip_time_nanoseconds",
+	); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAFPacketInputs(t *testing.T) {
+	server, beatURL := serveFixtures(t, map[string]string{
+		"/inputs/": "testdata/packetbeat/inputs.json",
+	})
+	defer server.Close()
+
+	c := NewInputsCollector(&BeatInfo{Beat: "packetbeat"}, server.Client(), beatURL)
+
+	expected := `
+# HELP packetbeat_input_packets_received_total inputs.packets_received_total
+# TYPE packetbeat_input_packets_received_total counter
+packetbeat_input_packets_received_total{device="eth0",id="eth0",type="af_packet"} 918231
+# HELP packetbeat_input_packets_dropped_total inputs.packets_dropped_total
+# TYPE packetbeat_input_packets_dropped_total counter
+packetbeat_input_packets_dropped_total{device="eth0",id="eth0",type="af_packet"} 17
+`
+
+	if err := testutil.CollectAndCompare(c, strings.NewReader(expected),
+		"packetbeat_input_packets_received_total",
+		"packetbeat_input_packets_dropped_total",
+	); err != nil {
+		t.Fatal(err)
+	}
+}
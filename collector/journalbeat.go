@@ -0,0 +1,64 @@
+package collector
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+//Journal json structure of the reader of a single journal
+type Journal struct {
+	Path           string  `json:"path"`
+	EntriesRead    float64 `json:"entries_read"`
+	EntriesSkipped float64 `json:"entries_skipped"`
+}
+
+//Journalbeat json structure
+type Journalbeat struct {
+	Journals map[string]Journal `json:"journals"`
+}
+
+type journalbeatCollector struct {
+	beatInfo       *BeatInfo
+	stats          *Stats
+	entriesRead    *prometheus.Desc
+	entriesSkipped *prometheus.Desc
+}
+
+// NewJournalbeatCollector constructor
+func NewJournalbeatCollector(beatInfo *BeatInfo, stats *Stats) prometheus.Collector {
+	return &journalbeatCollector{
+		beatInfo: beatInfo,
+		stats:    stats,
+		entriesRead: prometheus.NewDesc(
+			prometheus.BuildFQName(beatInfo.Beat, "journal", "entries_read"),
+			"journalbeat.journals.<id>.entries_read",
+			[]string{"journal"}, nil,
+		),
+		entriesSkipped: prometheus.NewDesc(
+			prometheus.BuildFQName(beatInfo.Beat, "journal", "entries_skipped"),
+			"journalbeat.journals.<id>.entries_skipped",
+			[]string{"journal"}, nil,
+		),
+	}
+}
+
+// Describe returns all descriptions of the collector.
+func (c *journalbeatCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.entriesRead
+	ch <- c.entriesSkipped
+}
+
+// Collect returns the current state of all metrics of the collector.
+func (c *journalbeatCollector) Collect(ch chan<- prometheus.Metric) {
+
+	for id, journal := range c.stats.Journalbeat.Journals {
+		// the local journal has no path, it is labelled with its id instead
+		label := journal.Path
+		if label == "" {
+			label = id
+		}
+
+		ch <- prometheus.MustNewConstMetric(c.entriesRead, prometheus.CounterValue, journal.EntriesRead, label)
+		ch <- prometheus.MustNewConstMetric(c.entriesSkipped, prometheus.CounterValue, journal.EntriesSkipped, label)
+	}
+
+}
@@ -0,0 +1,27 @@
+package collector
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestJournalbeatCollector(t *testing.T) {
+	c := NewJournalbeatCollector(&BeatInfo{Beat: "journalbeat"}, loadStats(t, "testdata/journalbeat/stats.json"))
+
+	expected := `
+# HELP journalbeat_journal_entries_read journalbeat.journals.<id>.entries_read
+# TYPE journalbeat_journal_entries_read counter
+journalbeat_journal_entries_read{journal="/var/log/journal"} 18422
+journalbeat_journal_entries_read{journal="journal_2"} 977
+# HELP journalbeat_journal_entries_skipped journalbeat.journals.<id>.entries_skipped
+# TYPE journalbeat_journal_entries_skipped counter
+journalbeat_journal_entries_skipped{journal="/var/log/journal"} 12
+journalbeat_journal_entries_skipped{journal="journal_2"} 0
+`
+
+	if err := testutil.CollectAndCompare(c, strings.NewReader(expected)); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -23,12 +23,15 @@ type LibBeatEvents struct {
 	Acked      float64 `json:"acked"`
 	Active     float64 `json:"active"`
 	Batches    float64 `json:"batches"`
+	DeadLetter float64 `json:"dead_letter"`
 	Dropped    float64 `json:"dropped"`
 	Duplicates float64 `json:"duplicates"`
 	Failed     float64 `json:"failed"`
 	Filtered   float64 `json:"filtered"`
 	Published  float64 `json:"published"`
 	Retry      float64 `json:"retry"`
+	TooMany    float64 `json:"toomany"`
+	Total      float64 `json:"total"`
 }
 
 //LibBeatOutputBytesErrors json structure
@@ -37,11 +40,28 @@ type LibBeatOutputBytesErrors struct {
 	Errors float64 `json:"errors"`
 }
 
+//LibBeatOutputWrite json structure
+type LibBeatOutputWrite struct {
+	LibBeatOutputBytesErrors
+	Latency struct {
+		Histogram Histogram `json:"histogram"`
+	} `json:"latency"`
+}
+
 //LibBeatOutput json structure
 type LibBeatOutput struct {
+	Batches struct {
+		Split float64 `json:"split"`
+	} `json:"batches"`
+	Elasticsearch struct {
+		BulkRequests struct {
+			Active    float64 `json:"active"`
+			Available float64 `json:"available"`
+		} `json:"bulk_requests"`
+	} `json:"elasticsearch"`
 	Events LibBeatEvents            `json:"events"`
 	Read   LibBeatOutputBytesErrors `json:"read"`
-	Write  LibBeatOutputBytesErrors `json:"write"`
+	Write  LibBeatOutputWrite       `json:"write"`
 	Type   string                   `json:"type"`
 }
 
@@ -55,18 +75,23 @@ type LibBeatPipeline struct {
 }
 
 type libbeatCollector struct {
-	beatInfo *BeatInfo
-	stats    *Stats
-	metrics  exportedMetrics
+	beatInfo   *BeatInfo
+	stats      *Stats
+	metrics    exportedMetrics
+	histograms exportedHistograms
+	outputType *prometheus.Desc
 }
 
-var libbeatOutputType *prometheus.Desc
-
 // NewLibBeatCollector constructor
 func NewLibBeatCollector(beatInfo *BeatInfo, stats *Stats) prometheus.Collector {
 	return &libbeatCollector{
 		beatInfo: beatInfo,
 		stats:    stats,
+		outputType: prometheus.NewDesc(
+			prometheus.BuildFQName(beatInfo.Beat, "libbeat", "output_total"),
+			"libbeat.output.type",
+			[]string{"type"}, nil,
+		),
 		metrics: exportedMetrics{
 			{
 				desc: prometheus.NewDesc(
@@ -222,6 +247,72 @@ func NewLibBeatCollector(beatInfo *BeatInfo, stats *Stats) prometheus.Collector
 				},
 				valType: prometheus.UntypedValue,
 			},
+			{
+				desc: prometheus.NewDesc(
+					prometheus.BuildFQName(beatInfo.Beat, "libbeat", "output_events"),
+					"libbeat.output.events",
+					nil, prometheus.Labels{"type": "dead_letter"},
+				),
+				eval: func(stats *Stats) float64 {
+					return stats.LibBeat.Output.Events.DeadLetter
+				},
+				valType: prometheus.UntypedValue,
+			},
+			{
+				desc: prometheus.NewDesc(
+					prometheus.BuildFQName(beatInfo.Beat, "libbeat", "output_events"),
+					"libbeat.output.events",
+					nil, prometheus.Labels{"type": "toomany"},
+				),
+				eval: func(stats *Stats) float64 {
+					return stats.LibBeat.Output.Events.TooMany
+				},
+				valType: prometheus.UntypedValue,
+			},
+			{
+				desc: prometheus.NewDesc(
+					prometheus.BuildFQName(beatInfo.Beat, "libbeat", "output_events"),
+					"libbeat.output.events",
+					nil, prometheus.Labels{"type": "total"},
+				),
+				eval: func(stats *Stats) float64 {
+					return stats.LibBeat.Output.Events.Total
+				},
+				valType: prometheus.UntypedValue,
+			},
+			{
+				desc: prometheus.NewDesc(
+					prometheus.BuildFQName(beatInfo.Beat, "libbeat", "output_batches_split_total"),
+					"libbeat.output.batches.split",
+					nil, nil,
+				),
+				eval: func(stats *Stats) float64 {
+					return stats.LibBeat.Output.Batches.Split
+				},
+				valType: prometheus.CounterValue,
+			},
+			{
+				desc: prometheus.NewDesc(
+					prometheus.BuildFQName(beatInfo.Beat, "libbeat", "output_elasticsearch_bulk_requests"),
+					"libbeat.output.elasticsearch.bulk_requests",
+					nil, prometheus.Labels{"state": "active"},
+				),
+				eval: func(stats *Stats) float64 {
+					return stats.LibBeat.Output.Elasticsearch.BulkRequests.Active
+				},
+				valType: prometheus.GaugeValue,
+			},
+			{
+				desc: prometheus.NewDesc(
+					prometheus.BuildFQName(beatInfo.Beat, "libbeat", "output_elasticsearch_bulk_requests"),
+					"libbeat.output.elasticsearch.bulk_requests",
+					nil, prometheus.Labels{"state": "available"},
+				),
+				eval: func(stats *Stats) float64 {
+					return stats.LibBeat.Output.Elasticsearch.BulkRequests.Available
+				},
+				valType: prometheus.GaugeValue,
+			},
 			{
 				desc: prometheus.NewDesc(
 					prometheus.BuildFQName(beatInfo.Beat, "libbeat", "pipeline_clients"),
@@ -311,6 +402,18 @@ func NewLibBeatCollector(beatInfo *BeatInfo, stats *Stats) prometheus.Collector
 				valType: prometheus.UntypedValue,
 			},
 		},
+		histograms: exportedHistograms{
+			{
+				desc: prometheus.NewDesc(
+					prometheus.BuildFQName(beatInfo.Beat, "libbeat", "output_write_latency_milliseconds"),
+					"libbeat.output.write.latency",
+					nil, nil,
+				),
+				eval: func(stats *Stats) Histogram {
+					return stats.LibBeat.Output.Write.Latency.Histogram
+				},
+			},
+		},
 	}
 }
 
@@ -321,13 +424,11 @@ func (c *libbeatCollector) Describe(ch chan<- *prometheus.Desc) {
 		ch <- metric.desc
 	}
 
-	libbeatOutputType = prometheus.NewDesc(
-		prometheus.BuildFQName(c.beatInfo.Beat, "libbeat", "output_total"),
-		"libbeat.output.type",
-		[]string{"type"}, nil,
-	)
+	for _, histogram := range c.histograms {
+		ch <- histogram.desc
+	}
 
-	ch <- libbeatOutputType
+	ch <- c.outputType
 
 }
 
@@ -338,7 +439,11 @@ func (c *libbeatCollector) Collect(ch chan<- prometheus.Metric) {
 		ch <- prometheus.MustNewConstMetric(i.desc, i.valType, i.eval(c.stats))
 	}
 
+	for _, i := range c.histograms {
+		ch <- newHistogramSummary(i.desc, i.eval(c.stats))
+	}
+
 	// output.type with dynamic label
-	ch <- prometheus.MustNewConstMetric(libbeatOutputType, prometheus.CounterValue, float64(1), c.stats.LibBeat.Output.Type)
+	ch <- prometheus.MustNewConstMetric(c.outputType, prometheus.CounterValue, float64(1), c.stats.LibBeat.Output.Type)
 
 }
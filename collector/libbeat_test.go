@@ -0,0 +1,62 @@
+package collector
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestLibBeatOutputTypePerBeat(t *testing.T) {
+	registry := prometheus.NewRegistry()
+
+	filebeatStats := &Stats{}
+	filebeatStats.LibBeat.Output.Type = "elasticsearch"
+
+	metricbeatStats := &Stats{}
+	metricbeatStats.LibBeat.Output.Type = "kafka"
+
+	registry.MustRegister(NewLibBeatCollector(&BeatInfo{Beat: "filebeat"}, filebeatStats))
+	registry.MustRegister(NewLibBeatCollector(&BeatInfo{Beat: "metricbeat"}, metricbeatStats))
+
+	expected := `
+# HELP filebeat_libbeat_output_total libbeat.output.type
+# TYPE filebeat_libbeat_output_total counter
+filebeat_libbeat_output_total{type="elasticsearch"} 1
+# HELP metricbeat_libbeat_output_total libbeat.output.type
+# TYPE metricbeat_libbeat_output_total counter
+metricbeat_libbeat_output_total{type="kafka"} 1
+`
+
+	if err := testutil.GatherAndCompare(registry, strings.NewReader(expected), "filebeat_libbeat_output_total", "metricbeat_libbeat_output_total"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLibBeatOutputWriteLatency(t *testing.T) {
+	stats := &Stats{}
+	stats.LibBeat.Output.Write.Latency.Histogram = Histogram{
+		Count: 4, Max: 92, Mean: 20.5, Median: 11, Min: 3, P75: 31, P95: 88, P99: 91, P999: 92,
+	}
+
+	c := NewLibBeatCollector(&BeatInfo{Beat: "filebeat"}, stats)
+
+	expected := `
+# HELP filebeat_libbeat_output_write_latency_milliseconds libbeat.output.write.latency
+# TYPE filebeat_libbeat_output_write_latency_milliseconds summary
+filebeat_libbeat_output_write_latency_milliseconds{quantile="0.5"} 11
+filebeat_libbeat_output_write_latency_milliseconds{quantile="0.75"} 31
+filebeat_libbeat_output_write_latency_milliseconds{quantile="0.95"} 88
+filebeat_libbeat_output_write_latency_milliseconds{quantile="0.99"} 91
+filebeat_libbeat_output_write_latency_milliseconds{quantile="0.999"} 92
+filebeat_libbeat_output_write_latency_milliseconds_sum 82
+filebeat_libbeat_output_write_latency_milliseconds_count 4
+`
+
+	if err := testutil.CollectAndCompare(c, strings.NewReader(expected),
+		"filebeat_libbeat_output_write_latency_milliseconds",
+	); err != nil {
+		t.Fatal(err)
+	}
+}
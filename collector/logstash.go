@@ -0,0 +1,209 @@
+package collector
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+//LogstashEvents json structure of event counters of a pipeline or plugin
+type LogstashEvents struct {
+	In                        float64 `json:"in"`
+	Filtered                  float64 `json:"filtered"`
+	Out                       float64 `json:"out"`
+	DurationInMillis          float64 `json:"duration_in_millis"`
+	QueuePushDurationInMillis float64 `json:"queue_push_duration_in_millis"`
+}
+
+//LogstashPlugin json structure of a pipeline plugin
+type LogstashPlugin struct {
+	ID     string         `json:"id"`
+	Name   string         `json:"name"`
+	Events LogstashEvents `json:"events"`
+}
+
+//LogstashQueue json structure of a pipeline queue, 7.x reports the sizes under capacity
+type LogstashQueue struct {
+	Type                string  `json:"type"`
+	EventsCount         float64 `json:"events_count"`
+	QueueSizeInBytes    float64 `json:"queue_size_in_bytes"`
+	MaxQueueSizeInBytes float64 `json:"max_queue_size_in_bytes"`
+	Capacity            struct {
+		QueueSizeInBytes    float64 `json:"queue_size_in_bytes"`
+		MaxQueueSizeInBytes float64 `json:"max_queue_size_in_bytes"`
+	} `json:"capacity"`
+}
+
+//LogstashPipeline json structure
+type LogstashPipeline struct {
+	Events  LogstashEvents `json:"events"`
+	Plugins struct {
+		Inputs  []LogstashPlugin `json:"inputs"`
+		Filters []LogstashPlugin `json:"filters"`
+		Outputs []LogstashPlugin `json:"outputs"`
+	} `json:"plugins"`
+	Queue LogstashQueue `json:"queue"`
+}
+
+//Logstash json structure of the /_node/stats endpoint
+type Logstash struct {
+	JVM struct {
+		Mem struct {
+			HeapUsedInBytes      float64 `json:"heap_used_in_bytes"`
+			HeapCommittedInBytes float64 `json:"heap_committed_in_bytes"`
+			HeapMaxInBytes       float64 `json:"heap_max_in_bytes"`
+		} `json:"mem"`
+	} `json:"jvm"`
+	Pipelines map[string]LogstashPipeline `json:"pipelines"`
+}
+
+type logstashCollector struct {
+	beatInfo         *BeatInfo
+	stats            *Stats
+	metrics          exportedMetrics
+	pipelineEvents   *prometheus.Desc
+	pipelineDuration *prometheus.Desc
+	pluginEvents     *prometheus.Desc
+	pluginDuration   *prometheus.Desc
+	queueEvents      *prometheus.Desc
+	queueSize        *prometheus.Desc
+	queueMaxSize     *prometheus.Desc
+}
+
+// NewLogstashCollector constructor
+func NewLogstashCollector(beatInfo *BeatInfo, stats *Stats) prometheus.Collector {
+	return &logstashCollector{
+		beatInfo: beatInfo,
+		stats:    stats,
+		metrics: exportedMetrics{
+			{
+				desc: prometheus.NewDesc(
+					prometheus.BuildFQName(beatInfo.Beat, "jvm", "heap_bytes"),
+					"jvm.mem.heap_<area>_in_bytes",
+					nil, prometheus.Labels{"area": "used"},
+				),
+				eval:    func(stats *Stats) float64 { return stats.Logstash.JVM.Mem.HeapUsedInBytes },
+				valType: prometheus.GaugeValue,
+			},
+			{
+				desc: prometheus.NewDesc(
+					prometheus.BuildFQName(beatInfo.Beat, "jvm", "heap_bytes"),
+					"jvm.mem.heap_<area>_in_bytes",
+					nil, prometheus.Labels{"area": "committed"},
+				),
+				eval:    func(stats *Stats) float64 { return stats.Logstash.JVM.Mem.HeapCommittedInBytes },
+				valType: prometheus.GaugeValue,
+			},
+			{
+				desc: prometheus.NewDesc(
+					prometheus.BuildFQName(beatInfo.Beat, "jvm", "heap_bytes"),
+					"jvm.mem.heap_<area>_in_bytes",
+					nil, prometheus.Labels{"area": "max"},
+				),
+				eval:    func(stats *Stats) float64 { return stats.Logstash.JVM.Mem.HeapMaxInBytes },
+				valType: prometheus.GaugeValue,
+			},
+		},
+		pipelineEvents: prometheus.NewDesc(
+			prometheus.BuildFQName(beatInfo.Beat, "pipeline", "events_total"),
+			"pipelines.<id>.events",
+			[]string{"pipeline", "type"}, nil,
+		),
+		pipelineDuration: prometheus.NewDesc(
+			prometheus.BuildFQName(beatInfo.Beat, "pipeline", "duration_seconds_total"),
+			"pipelines.<id>.events.duration_in_millis and queue_push_duration_in_millis",
+			[]string{"pipeline", "type"}, nil,
+		),
+		pluginEvents: prometheus.NewDesc(
+			prometheus.BuildFQName(beatInfo.Beat, "plugin", "events_total"),
+			"pipelines.<id>.plugins.<kind>.events",
+			[]string{"pipeline", "kind", "plugin", "plugin_id", "type"}, nil,
+		),
+		pluginDuration: prometheus.NewDesc(
+			prometheus.BuildFQName(beatInfo.Beat, "plugin", "duration_seconds_total"),
+			"pipelines.<id>.plugins.<kind>.events.duration_in_millis, queue_push_duration_in_millis for inputs",
+			[]string{"pipeline", "kind", "plugin", "plugin_id"}, nil,
+		),
+		queueEvents: prometheus.NewDesc(
+			prometheus.BuildFQName(beatInfo.Beat, "queue", "events"),
+			"pipelines.<id>.queue.events_count",
+			[]string{"pipeline", "type"}, nil,
+		),
+		queueSize: prometheus.NewDesc(
+			prometheus.BuildFQName(beatInfo.Beat, "queue", "size_bytes"),
+			"pipelines.<id>.queue.queue_size_in_bytes",
+			[]string{"pipeline", "type"}, nil,
+		),
+		queueMaxSize: prometheus.NewDesc(
+			prometheus.BuildFQName(beatInfo.Beat, "queue", "max_size_bytes"),
+			"pipelines.<id>.queue.max_queue_size_in_bytes",
+			[]string{"pipeline", "type"}, nil,
+		),
+	}
+}
+
+// Describe returns all descriptions of the collector.
+func (c *logstashCollector) Describe(ch chan<- *prometheus.Desc) {
+
+	for _, metric := range c.metrics {
+		ch <- metric.desc
+	}
+	ch <- c.pipelineEvents
+	ch <- c.pipelineDuration
+	ch <- c.pluginEvents
+	ch <- c.pluginDuration
+	ch <- c.queueEvents
+	ch <- c.queueSize
+	ch <- c.queueMaxSize
+
+}
+
+// Collect returns the current state of all metrics of the collector.
+func (c *logstashCollector) Collect(ch chan<- prometheus.Metric) {
+
+	for _, i := range c.metrics {
+		ch <- prometheus.MustNewConstMetric(i.desc, i.valType, i.eval(c.stats))
+	}
+
+	for id, pipeline := range c.stats.Logstash.Pipelines {
+		events := pipeline.Events
+
+		ch <- prometheus.MustNewConstMetric(c.pipelineEvents, prometheus.CounterValue, events.In, id, "in")
+		ch <- prometheus.MustNewConstMetric(c.pipelineEvents, prometheus.CounterValue, events.Filtered, id, "filtered")
+		ch <- prometheus.MustNewConstMetric(c.pipelineEvents, prometheus.CounterValue, events.Out, id, "out")
+		ch <- prometheus.MustNewConstMetric(c.pipelineDuration, prometheus.CounterValue, events.DurationInMillis/1000, id, "processing")
+		ch <- prometheus.MustNewConstMetric(c.pipelineDuration, prometheus.CounterValue, events.QueuePushDurationInMillis/1000, id, "queue_push")
+
+		c.collectPlugins(ch, id, "input", pipeline.Plugins.Inputs)
+		c.collectPlugins(ch, id, "filter", pipeline.Plugins.Filters)
+		c.collectPlugins(ch, id, "output", pipeline.Plugins.Outputs)
+
+		queue := pipeline.Queue
+		if queue.QueueSizeInBytes == 0 && queue.MaxQueueSizeInBytes == 0 {
+			queue.QueueSizeInBytes = queue.Capacity.QueueSizeInBytes
+			queue.MaxQueueSizeInBytes = queue.Capacity.MaxQueueSizeInBytes
+		}
+
+		ch <- prometheus.MustNewConstMetric(c.queueEvents, prometheus.GaugeValue, queue.EventsCount, id, queue.Type)
+		ch <- prometheus.MustNewConstMetric(c.queueSize, prometheus.GaugeValue, queue.QueueSizeInBytes, id, queue.Type)
+		ch <- prometheus.MustNewConstMetric(c.queueMaxSize, prometheus.GaugeValue, queue.MaxQueueSizeInBytes, id, queue.Type)
+	}
+
+}
+
+func (c *logstashCollector) collectPlugins(ch chan<- prometheus.Metric, pipeline string, kind string, plugins []LogstashPlugin) {
+	for _, plugin := range plugins {
+		// inputs only count events out, outputs only events in
+		if kind != "input" {
+			ch <- prometheus.MustNewConstMetric(c.pluginEvents, prometheus.CounterValue, plugin.Events.In, pipeline, kind, plugin.Name, plugin.ID, "in")
+		}
+		if kind != "output" {
+			ch <- prometheus.MustNewConstMetric(c.pluginEvents, prometheus.CounterValue, plugin.Events.Out, pipeline, kind, plugin.Name, plugin.ID, "out")
+		}
+
+		// inputs report the time spent pushing into the queue instead of a processing duration
+		duration := plugin.Events.DurationInMillis
+		if kind == "input" {
+			duration = plugin.Events.QueuePushDurationInMillis
+		}
+		ch <- prometheus.MustNewConstMetric(c.pluginDuration, prometheus.CounterValue, duration/1000, pipeline, kind, plugin.Name, plugin.ID)
+	}
+}
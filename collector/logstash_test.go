@@ -0,0 +1,45 @@
+package collector
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestLogstashCollector(t *testing.T) {
+	server, beatURL := serveFixtures(t, map[string]string{
+		"/_node/stats": "testdata/logstash/node_stats.json",
+	})
+	defer server.Close()
+
+	c := NewMainCollector(server.Client(), beatURL, "beat_exporter", &BeatInfo{Beat: "logstash", Version: "8.11.1"}, Options{})
+
+	expected := `
+# HELP logstash_up Target up
+# TYPE logstash_up gauge
+logstash_up 1
+# HELP logstash_jvm_heap_bytes jvm.mem.heap_<area>_in_bytes
+# TYPE logstash_jvm_heap_bytes gauge
+logstash_jvm_heap_bytes{area="committed"} 1.073741824e+09
+logstash_jvm_heap_bytes{area="max"} 1.073741824e+09
+logstash_jvm_heap_bytes{area="used"} 2.92057776e+08
+# HELP logstash_plugin_duration_seconds_total pipelines.<id>.plugins.<kind>.events.duration_in_millis, queue_push_duration_in_millis for inputs
+# TYPE logstash_plugin_duration_seconds_total counter
+logstash_plugin_duration_seconds_total{kind="filter",pipeline="main",plugin="grok",plugin_id="grok-nginx"} 88.214
+logstash_plugin_duration_seconds_total{kind="input",pipeline="main",plugin="beats",plugin_id="beats-5044"} 5.31
+logstash_plugin_duration_seconds_total{kind="output",pipeline="main",plugin="elasticsearch",plugin_id="es-logs"} 301.455
+# HELP logstash_queue_size_bytes pipelines.<id>.queue.queue_size_in_bytes
+# TYPE logstash_queue_size_bytes gauge
+logstash_queue_size_bytes{pipeline="main",type="persisted"} 88342
+`
+
+	if err := testutil.CollectAndCompare(c, strings.NewReader(expected),
+		"logstash_up",
+		"logstash_jvm_heap_bytes",
+		"logstash_plugin_duration_seconds_total",
+		"logstash_queue_size_bytes",
+	); err != nil {
+		t.Fatal(err)
+	}
+}
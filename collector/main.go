@@ -2,6 +2,7 @@ package collector
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/prometheus/client_golang/prometheus"
 	log "github.com/sirupsen/logrus"
+	"github.com/trustpilot/beat-exporter/internal/beatconfig"
 )
 
 type mainCollector struct {
@@ -22,14 +24,32 @@ type mainCollector struct {
 	targetDesc *prometheus.Desc
 	targetUp   *prometheus.Desc
 	metrics    exportedMetrics
-	systemBeat bool
+	options    Options
+}
+
+// Options configures the optional collectors
+type Options struct {
+	SystemBeat         bool
+	DataPath           string
+	RegistryMaxFiles   int
+	RegistryPathGroups []string
+	DeletedFiles       bool
+	Process            bool
+	PID                int
+	PIDFile            string
+	SocketPath         string
+	ProcessName        string
+	Unharvested        bool
+	FilebeatInputs     []beatconfig.Input
+	// AgentProxy is set for components scraped through the Elastic Agent, whose proxy does not serve /inputs/
+	AgentProxy bool
 }
 
 // HackfixRegex regex to replace JSON part
 var HackfixRegex = regexp.MustCompile("\"time\":(\\d+)") // replaces time:123 to time.ms:123, only filebeat has different naming of time metric
 
 // NewMainCollector constructor
-func NewMainCollector(client *http.Client, url *url.URL, name string, beatInfo *BeatInfo, systemBeat bool) prometheus.Collector {
+func NewMainCollector(client *http.Client, url *url.URL, name string, beatInfo *BeatInfo, options Options) prometheus.Collector {
 	instance := fmt.Sprintf("%s:%s", url.Hostname(), url.Port())
 	beat := &mainCollector{
 		Collectors: make(map[string]prometheus.Collector),
@@ -48,9 +68,9 @@ func NewMainCollector(client *http.Client, url *url.URL, name string, beatInfo *
 			nil,
 			nil),
 
-		beatInfo:   beatInfo,
-		metrics:    exportedMetrics{},
-		systemBeat: systemBeat,
+		beatInfo: beatInfo,
+		metrics:  exportedMetrics{},
+		options:  options,
 	}
 
 	beat.Collectors["system"] = NewSystemCollector(beatInfo, beat.Stats)
@@ -61,6 +81,32 @@ func NewMainCollector(client *http.Client, url *url.URL, name string, beatInfo *
 	beat.Collectors["metricbeat"] = NewMetricbeatCollector(beatInfo, beat.Stats)
 	beat.Collectors["auditd"] = NewAuditdCollector(beatInfo, beat.Stats)
 	beat.Collectors["apmserver"] = NewApmserverCollector(beatInfo, beat.Stats)
+	beat.Collectors["packetbeat"] = NewPacketbeatCollector(beatInfo, beat.Stats)
+	beat.Collectors["heartbeat"] = NewHeartbeatCollector(beatInfo, beat.Stats)
+	beat.Collectors["auditbeat"] = NewAuditbeatCollector(beatInfo, beat.Stats)
+	beat.Collectors["journalbeat"] = NewJournalbeatCollector(beatInfo, beat.Stats)
+	beat.Collectors["functionbeat"] = NewFunctionbeatCollector(beatInfo, beat.Stats)
+	beat.Collectors["osquerybeat"] = NewOsquerybeatCollector(beatInfo, beat.Stats)
+	beat.Collectors["fleetserver"] = NewFleetServerCollector(beatInfo, beat.Stats)
+	beat.Collectors["logstash"] = NewLogstashCollector(beatInfo, beat.Stats)
+	beat.Collectors["diskqueue"] = NewDiskQueueCollector(beatInfo, options.DataPath)
+	// the registry and unharvested collectors share the parsed registry
+	registry := NewRegistryReader(options.DataPath)
+	beat.Collectors["registry"] = NewRegistryCollector(beatInfo, registry, options.RegistryMaxFiles, options.RegistryPathGroups)
+	beat.Collectors["inputs"] = NewInputsCollector(beatInfo, client, url)
+	beat.Collectors["unharvested"] = NewUnharvestedCollector(beatInfo, registry, options.FilebeatInputs)
+
+	process := beatProcess{
+		knownPID:   options.PID,
+		pidFile:    options.PIDFile,
+		socketPath: options.SocketPath,
+		name:       options.ProcessName,
+	}
+	if process.name == "" {
+		process.name = beatInfo.Beat
+	}
+	beat.Collectors["deletedfiles"] = NewDeletedFilesCollector(beatInfo, process)
+	beat.Collectors["process"] = NewProcessCollector(beatInfo, process)
 
 	return beat
 }
@@ -75,22 +121,66 @@ func (b *mainCollector) Describe(ch chan<- *prometheus.Desc) {
 	}
 
 	// standard collectors for all types of beats
-	if b.systemBeat {
+	if b.options.SystemBeat {
 		b.Collectors["system"].Describe(ch)
 	}
-	b.Collectors["beat"].Describe(ch)
-	b.Collectors["libbeat"].Describe(ch)
-	b.Collectors["auditd"].Describe(ch)
+	if b.options.DataPath != "" {
+		b.Collectors["diskqueue"].Describe(ch)
+	}
+	if b.options.DeletedFiles {
+		b.Collectors["deletedfiles"].Describe(ch)
+	}
+	if b.options.Process {
+		b.Collectors["process"].Describe(ch)
+	}
+	// logstash only shares the process level collectors with beats
+	if b.beatInfo.Beat != "logstash" {
+		b.Collectors["beat"].Describe(ch)
+		b.Collectors["libbeat"].Describe(ch)
+	}
 
 	// Customized collectors per beat type
 	switch b.beatInfo.Beat {
 	case "filebeat":
 		b.Collectors["filebeat"].Describe(ch)
 		b.Collectors["registrar"].Describe(ch)
+		if !b.options.AgentProxy {
+			b.Collectors["inputs"].Describe(ch)
+		}
+		if b.options.DataPath != "" {
+			b.Collectors["registry"].Describe(ch)
+		}
+		if b.options.DataPath != "" && b.options.Unharvested {
+			b.Collectors["unharvested"].Describe(ch)
+		}
 	case "metricbeat":
 		b.Collectors["metricbeat"].Describe(ch)
 	case "apmserver":
 		b.Collectors["apmserver"].Describe(ch)
+	case "packetbeat":
+		b.Collectors["packetbeat"].Describe(ch)
+		if !b.options.AgentProxy {
+			b.Collectors["inputs"].Describe(ch)
+		}
+	case "heartbeat":
+		b.Collectors["heartbeat"].Describe(ch)
+	case "winlogbeat":
+		if !b.options.AgentProxy {
+			b.Collectors["inputs"].Describe(ch)
+		}
+	case "auditbeat":
+		b.Collectors["auditd"].Describe(ch)
+		b.Collectors["auditbeat"].Describe(ch)
+	case "journalbeat":
+		b.Collectors["journalbeat"].Describe(ch)
+	case "functionbeat":
+		b.Collectors["functionbeat"].Describe(ch)
+	case "osquerybeat":
+		b.Collectors["osquerybeat"].Describe(ch)
+	case "fleetserver":
+		b.Collectors["fleetserver"].Describe(ch)
+	case "logstash":
+		b.Collectors["logstash"].Describe(ch)
 	}
 
 }
@@ -101,7 +191,7 @@ func (b *mainCollector) Collect(ch chan<- prometheus.Metric) {
 	err := b.fetchStatsEndpoint()
 	if err != nil {
 		ch <- prometheus.MustNewConstMetric(b.targetUp, prometheus.GaugeValue, float64(0)) // set target down
-		log.Errorf("Failed getting /stats endpoint of target: " + err.Error())
+		log.Errorf("Failed getting stats endpoint of target: " + err.Error())
 		return
 	}
 
@@ -113,29 +203,78 @@ func (b *mainCollector) Collect(ch chan<- prometheus.Metric) {
 	}
 
 	// standard collectors for all types of beats
-	if b.systemBeat {
+	if b.options.SystemBeat {
 		b.Collectors["system"].Collect(ch)
 	}
-	b.Collectors["beat"].Collect(ch)
-	b.Collectors["libbeat"].Collect(ch)
-	b.Collectors["auditd"].Collect(ch)
+	if b.options.DataPath != "" {
+		b.Collectors["diskqueue"].Collect(ch)
+	}
+	if b.options.DeletedFiles {
+		b.Collectors["deletedfiles"].Collect(ch)
+	}
+	if b.options.Process {
+		b.Collectors["process"].Collect(ch)
+	}
+	// logstash only shares the process level collectors with beats
+	if b.beatInfo.Beat != "logstash" {
+		b.Collectors["beat"].Collect(ch)
+		b.Collectors["libbeat"].Collect(ch)
+	}
 
 	// Customized collectors per beat type
 	switch b.beatInfo.Beat {
 	case "filebeat":
 		b.Collectors["filebeat"].Collect(ch)
 		b.Collectors["registrar"].Collect(ch)
+		if !b.options.AgentProxy {
+			b.Collectors["inputs"].Collect(ch)
+		}
+		if b.options.DataPath != "" {
+			b.Collectors["registry"].Collect(ch)
+		}
+		if b.options.DataPath != "" && b.options.Unharvested {
+			b.Collectors["unharvested"].Collect(ch)
+		}
 	case "metricbeat":
 		b.Collectors["metricbeat"].Collect(ch)
 	case "apmserver":
 		b.Collectors["apmserver"].Collect(ch)
+	case "packetbeat":
+		b.Collectors["packetbeat"].Collect(ch)
+		if !b.options.AgentProxy {
+			b.Collectors["inputs"].Collect(ch)
+		}
+	case "heartbeat":
+		b.Collectors["heartbeat"].Collect(ch)
+	case "winlogbeat":
+		if !b.options.AgentProxy {
+			b.Collectors["inputs"].Collect(ch)
+		}
+	case "auditbeat":
+		b.Collectors["auditd"].Collect(ch)
+		b.Collectors["auditbeat"].Collect(ch)
+	case "journalbeat":
+		b.Collectors["journalbeat"].Collect(ch)
+	case "functionbeat":
+		b.Collectors["functionbeat"].Collect(ch)
+	case "osquerybeat":
+		b.Collectors["osquerybeat"].Collect(ch)
+	case "fleetserver":
+		b.Collectors["fleetserver"].Collect(ch)
+	case "logstash":
+		b.Collectors["logstash"].Collect(ch)
 	}
 
 }
 
 func (b *mainCollector) fetchStatsEndpoint() error {
 
-	response, err := b.client.Get(b.beatURL.String() + "/stats")
+	statsPath := "/stats"
+	if b.beatInfo.Beat == "logstash" {
+		statsPath = "/_node/stats"
+	}
+
+	response, err := b.client.Get(b.beatURL.String() + statsPath)
 	if err != nil {
 		log.Errorf("Could not fetch stats endpoint of target: %v", b.beatURL.String())
 		return err
@@ -150,7 +289,12 @@ func (b *mainCollector) fetchStatsEndpoint() error {
 	}
 
 	// @TODO remove this when filebeat stats endpoint output matches all other beats output
-	bodyBytes = HackfixRegex.ReplaceAll(bodyBytes, []byte("\"time\":{\"ms\":$1}"))
+	if b.beatInfo.Beat != "logstash" {
+		bodyBytes = HackfixRegex.ReplaceAll(bodyBytes, []byte("\"time\":{\"ms\":$1}"))
+	}
+
+	// json merges into existing maps, start over so entries the beat no longer reports disappear
+	*b.Stats = Stats{}
 
 	err = json.Unmarshal(bodyBytes, &b.Stats)
 	if err != nil {
@@ -160,3 +304,37 @@ func (b *mainCollector) fetchStatsEndpoint() error {
 
 	return nil
 }
+
+// errNotFound is returned by fetchJSON for endpoints the target does not serve
+var errNotFound = errors.New("endpoint not found")
+
+// fetchJSON decodes the response of endpoint into v, leaving logging to the caller
+func fetchJSON(client *http.Client, endpoint string, v interface{}) error {
+
+	response, err := client.Get(endpoint)
+	if err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusNotFound {
+		return errNotFound
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code %d from %v", response.StatusCode, endpoint)
+	}
+
+	bodyBytes, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return fmt.Errorf("can't read body of %v: %v", endpoint, err)
+	}
+
+	err = json.Unmarshal(bodyBytes, v)
+	if err != nil {
+		return fmt.Errorf("could not parse JSON response of %v: %v", endpoint, err)
+	}
+
+	return nil
+}
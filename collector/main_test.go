@@ -0,0 +1,59 @@
+package collector
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+// serveFixtures serves each fixture file of testdata at its endpoint path, any other path is not found
+func serveFixtures(t *testing.T, fixtures map[string]string) (*httptest.Server, *url.URL) {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		file, ok := fixtures[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		body, err := ioutil.ReadFile(file)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(body)
+	}))
+
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		server.Close()
+		t.Fatal(err)
+	}
+
+	return server, serverURL
+}
+
+// loadStats decodes a recorded /stats fixture the way the main collector decodes the endpoint
+func loadStats(t *testing.T, file string) *Stats {
+	t.Helper()
+
+	body, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body = HackfixRegex.ReplaceAll(body, []byte("\"time\":{\"ms\":$1}"))
+
+	stats := &Stats{}
+	if err := json.Unmarshal(body, stats); err != nil {
+		t.Fatal(err)
+	}
+
+	return stats
+}
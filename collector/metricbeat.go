@@ -1,222 +1,128 @@
 package collector
 
 import (
+	"encoding/json"
+
 	"github.com/prometheus/client_golang/prometheus"
 )
 
 //MetricbeatEvent json structure
 type MetricbeatEvent struct {
+	Events   float64 `json:"events"`
 	Failures float64 `json:"failures"`
 	Success  float64 `json:"success"`
 }
 
-//Metricbeat json structure
-type Metricbeat struct {
-	System struct {
-		CPU            MetricbeatEvent `json:"cpu"`
-		Filesystem     MetricbeatEvent `json:"filesystem"`
-		Fsstat         MetricbeatEvent `json:"fsstat"`
-		Load           MetricbeatEvent `json:"load"`
-		Memory         MetricbeatEvent `json:"memory"`
-		Network        MetricbeatEvent `json:"network"`
-		Process        MetricbeatEvent `json:"process"`
-		ProcessSummary MetricbeatEvent `json:"process_summary"`
-		Uptime         MetricbeatEvent `json:"uptime"`
-	} `json:"system"`
+//Metricbeat json structure, counters keyed by module and metricset name
+type Metricbeat map[string]map[string]MetricbeatEvent
+
+// UnmarshalJSON skips entries that are not metricset counters instead of failing the whole stats response
+func (m *Metricbeat) UnmarshalJSON(data []byte) error {
+	var modules map[string]json.RawMessage
+
+	if err := json.Unmarshal(data, &modules); err != nil {
+		return err
+	}
+
+	parsed := make(Metricbeat, len(modules))
+
+	for module, raw := range modules {
+		var metricsets map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &metricsets); err != nil {
+			continue
+		}
+
+		parsed[module] = make(map[string]MetricbeatEvent, len(metricsets))
+
+		for metricset, raw := range metricsets {
+			var event MetricbeatEvent
+			if err := json.Unmarshal(raw, &event); err != nil {
+				continue
+			}
+			parsed[module][metricset] = event
+		}
+	}
+
+	*m = parsed
+
+	return nil
+}
+
+// metricbeatLegacySystem are the system metricsets exported as <beat>_metricbeat_system_<metricset>{event}
+// before every module was exported, kept until dashboards moved to <beat>_metricset_*
+var metricbeatLegacySystem = []string{
+	"cpu", "filesystem", "fsstat", "load", "memory", "network", "process", "process_summary", "uptime",
 }
 
 type metricbeatCollector struct {
-	beatInfo *BeatInfo
-	stats    *Stats
-	metrics  exportedMetrics
+	beatInfo     *BeatInfo
+	stats        *Stats
+	events       *prometheus.Desc
+	success      *prometheus.Desc
+	failures     *prometheus.Desc
+	legacySystem map[string]*prometheus.Desc
 }
 
 // NewMetricbeatCollector constructor
 func NewMetricbeatCollector(beatInfo *BeatInfo, stats *Stats) prometheus.Collector {
+	legacySystem := make(map[string]*prometheus.Desc, len(metricbeatLegacySystem))
+	for _, metricset := range metricbeatLegacySystem {
+		legacySystem[metricset] = prometheus.NewDesc(
+			prometheus.BuildFQName(beatInfo.Beat, "metricbeat_system", metricset),
+			"system."+metricset+" (deprecated, use "+beatInfo.Beat+"_metricset_*)",
+			[]string{"event"}, nil,
+		)
+	}
+
 	return &metricbeatCollector{
-		beatInfo: beatInfo,
-		stats:    stats,
-		metrics: exportedMetrics{
-			{
-				desc: prometheus.NewDesc(
-					prometheus.BuildFQName(beatInfo.Beat, "metricbeat_system", "cpu"),
-					"system.cpu",
-					nil, prometheus.Labels{"event": "success"},
-				),
-				eval:    func(stats *Stats) float64 { return stats.Metricbeat.System.CPU.Success },
-				valType: prometheus.CounterValue,
-			},
-			{
-				desc: prometheus.NewDesc(
-					prometheus.BuildFQName(beatInfo.Beat, "metricbeat_system", "cpu"),
-					"system.cpu",
-					nil, prometheus.Labels{"event": "failures"},
-				),
-				eval:    func(stats *Stats) float64 { return stats.Metricbeat.System.CPU.Failures },
-				valType: prometheus.CounterValue,
-			},
-			{
-				desc: prometheus.NewDesc(
-					prometheus.BuildFQName(beatInfo.Beat, "metricbeat_system", "filesystem"),
-					"system.filesystem",
-					nil, prometheus.Labels{"event": "success"},
-				),
-				eval:    func(stats *Stats) float64 { return stats.Metricbeat.System.Filesystem.Success },
-				valType: prometheus.CounterValue,
-			},
-			{
-				desc: prometheus.NewDesc(
-					prometheus.BuildFQName(beatInfo.Beat, "metricbeat_system", "filesystem"),
-					"system.filesystem",
-					nil, prometheus.Labels{"event": "failures"},
-				),
-				eval:    func(stats *Stats) float64 { return stats.Metricbeat.System.Filesystem.Failures },
-				valType: prometheus.CounterValue,
-			},
-			{
-				desc: prometheus.NewDesc(
-					prometheus.BuildFQName(beatInfo.Beat, "metricbeat_system", "fsstat"),
-					"system.fsstat",
-					nil, prometheus.Labels{"event": "success"},
-				),
-				eval:    func(stats *Stats) float64 { return stats.Metricbeat.System.Fsstat.Success },
-				valType: prometheus.CounterValue,
-			},
-			{
-				desc: prometheus.NewDesc(
-					prometheus.BuildFQName(beatInfo.Beat, "metricbeat_system", "fsstat"),
-					"system.fsstat",
-					nil, prometheus.Labels{"event": "failures"},
-				),
-				eval:    func(stats *Stats) float64 { return stats.Metricbeat.System.Fsstat.Failures },
-				valType: prometheus.CounterValue,
-			},
-			{
-				desc: prometheus.NewDesc(
-					prometheus.BuildFQName(beatInfo.Beat, "metricbeat_system", "load"),
-					"system.load",
-					nil, prometheus.Labels{"event": "success"},
-				),
-				eval:    func(stats *Stats) float64 { return stats.Metricbeat.System.Load.Success },
-				valType: prometheus.CounterValue,
-			},
-			{
-				desc: prometheus.NewDesc(
-					prometheus.BuildFQName(beatInfo.Beat, "metricbeat_system", "load"),
-					"system.load",
-					nil, prometheus.Labels{"event": "failures"},
-				),
-				eval:    func(stats *Stats) float64 { return stats.Metricbeat.System.Load.Failures },
-				valType: prometheus.CounterValue,
-			},
-			{
-				desc: prometheus.NewDesc(
-					prometheus.BuildFQName(beatInfo.Beat, "metricbeat_system", "memory"),
-					"system.memory",
-					nil, prometheus.Labels{"event": "success"},
-				),
-				eval:    func(stats *Stats) float64 { return stats.Metricbeat.System.Memory.Success },
-				valType: prometheus.CounterValue,
-			},
-			{
-				desc: prometheus.NewDesc(
-					prometheus.BuildFQName(beatInfo.Beat, "metricbeat_system", "memory"),
-					"system.memory",
-					nil, prometheus.Labels{"event": "failures"},
-				),
-				eval:    func(stats *Stats) float64 { return stats.Metricbeat.System.Memory.Failures },
-				valType: prometheus.CounterValue,
-			},
-			{
-				desc: prometheus.NewDesc(
-					prometheus.BuildFQName(beatInfo.Beat, "metricbeat_system", "network"),
-					"system.network",
-					nil, prometheus.Labels{"event": "success"},
-				),
-				eval:    func(stats *Stats) float64 { return stats.Metricbeat.System.Network.Success },
-				valType: prometheus.CounterValue,
-			},
-			{
-				desc: prometheus.NewDesc(
-					prometheus.BuildFQName(beatInfo.Beat, "metricbeat_system", "network"),
-					"system.network",
-					nil, prometheus.Labels{"event": "failures"},
-				),
-				eval:    func(stats *Stats) float64 { return stats.Metricbeat.System.Network.Failures },
-				valType: prometheus.CounterValue,
-			},
-			{
-				desc: prometheus.NewDesc(
-					prometheus.BuildFQName(beatInfo.Beat, "metricbeat_system", "process"),
-					"system.process",
-					nil, prometheus.Labels{"event": "success"},
-				),
-				eval:    func(stats *Stats) float64 { return stats.Metricbeat.System.Process.Success },
-				valType: prometheus.CounterValue,
-			},
-			{
-				desc: prometheus.NewDesc(
-					prometheus.BuildFQName(beatInfo.Beat, "metricbeat_system", "process"),
-					"system.process",
-					nil, prometheus.Labels{"event": "failures"},
-				),
-				eval:    func(stats *Stats) float64 { return stats.Metricbeat.System.Process.Failures },
-				valType: prometheus.CounterValue,
-			},
-			{
-				desc: prometheus.NewDesc(
-					prometheus.BuildFQName(beatInfo.Beat, "metricbeat_system", "process_summary"),
-					"system.process_summary",
-					nil, prometheus.Labels{"event": "success"},
-				),
-				eval:    func(stats *Stats) float64 { return stats.Metricbeat.System.ProcessSummary.Success },
-				valType: prometheus.CounterValue,
-			},
-			{
-				desc: prometheus.NewDesc(
-					prometheus.BuildFQName(beatInfo.Beat, "metricbeat_system", "process_summary"),
-					"system.process_summary",
-					nil, prometheus.Labels{"event": "failures"},
-				),
-				eval:    func(stats *Stats) float64 { return stats.Metricbeat.System.ProcessSummary.Failures },
-				valType: prometheus.CounterValue,
-			},
-			{
-				desc: prometheus.NewDesc(
-					prometheus.BuildFQName(beatInfo.Beat, "metricbeat_system", "uptime"),
-					"system.uptime",
-					nil, prometheus.Labels{"event": "success"},
-				),
-				eval:    func(stats *Stats) float64 { return stats.Metricbeat.System.Uptime.Success },
-				valType: prometheus.CounterValue,
-			},
-			{
-				desc: prometheus.NewDesc(
-					prometheus.BuildFQName(beatInfo.Beat, "metricbeat_system", "uptime"),
-					"system.uptime",
-					nil, prometheus.Labels{"event": "failures"},
-				),
-				eval:    func(stats *Stats) float64 { return stats.Metricbeat.System.Uptime.Failures },
-				valType: prometheus.CounterValue,
-			},
-		},
+		beatInfo:     beatInfo,
+		stats:        stats,
+		legacySystem: legacySystem,
+		events: prometheus.NewDesc(
+			prometheus.BuildFQName(beatInfo.Beat, "metricset", "events"),
+			"metricbeat.<module>.<metricset>.events",
+			[]string{"module", "metricset"}, nil,
+		),
+		success: prometheus.NewDesc(
+			prometheus.BuildFQName(beatInfo.Beat, "metricset", "success"),
+			"metricbeat.<module>.<metricset>.success",
+			[]string{"module", "metricset"}, nil,
+		),
+		failures: prometheus.NewDesc(
+			prometheus.BuildFQName(beatInfo.Beat, "metricset", "failures"),
+			"metricbeat.<module>.<metricset>.failures",
+			[]string{"module", "metricset"}, nil,
+		),
 	}
 }
 
 // Describe returns all descriptions of the collector.
 func (c *metricbeatCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.events
+	ch <- c.success
+	ch <- c.failures
 
-	for _, metric := range c.metrics {
-		ch <- metric.desc
+	for _, desc := range c.legacySystem {
+		ch <- desc
 	}
-
 }
 
 // Collect returns the current state of all metrics of the collector.
 func (c *metricbeatCollector) Collect(ch chan<- prometheus.Metric) {
 
-	for _, i := range c.metrics {
-		ch <- prometheus.MustNewConstMetric(i.desc, i.valType, i.eval(c.stats))
+	for module, metricsets := range c.stats.Metricbeat {
+		for metricset, event := range metricsets {
+			ch <- prometheus.MustNewConstMetric(c.events, prometheus.CounterValue, event.Events, module, metricset)
+			ch <- prometheus.MustNewConstMetric(c.success, prometheus.CounterValue, event.Success, module, metricset)
+			ch <- prometheus.MustNewConstMetric(c.failures, prometheus.CounterValue, event.Failures, module, metricset)
+		}
+	}
+
+	for metricset, desc := range c.legacySystem {
+		event := c.stats.Metricbeat["system"][metricset]
+
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.CounterValue, event.Success, "success")
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.CounterValue, event.Failures, "failures")
 	}
 
 }
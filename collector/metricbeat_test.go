@@ -0,0 +1,53 @@
+package collector
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMetricbeatCollector(t *testing.T) {
+	c := NewMetricbeatCollector(&BeatInfo{Beat: "metricbeat"}, loadStats(t, "testdata/metricbeat/stats.json"))
+
+	expected := `
+# HELP metricbeat_metricset_events metricbeat.<module>.<metricset>.events
+# TYPE metricbeat_metricset_events counter
+metricbeat_metricset_events{metricset="container",module="docker"} 1440
+metricbeat_metricset_events{metricset="cpu",module="system"} 360
+metricbeat_metricset_events{metricset="load",module="system"} 358
+# HELP metricbeat_metricset_success metricbeat.<module>.<metricset>.success
+# TYPE metricbeat_metricset_success counter
+metricbeat_metricset_success{metricset="container",module="docker"} 1436
+metricbeat_metricset_success{metricset="cpu",module="system"} 360
+metricbeat_metricset_success{metricset="load",module="system"} 356
+# HELP metricbeat_metricset_failures metricbeat.<module>.<metricset>.failures
+# TYPE metricbeat_metricset_failures counter
+metricbeat_metricset_failures{metricset="container",module="docker"} 4
+metricbeat_metricset_failures{metricset="cpu",module="system"} 0
+metricbeat_metricset_failures{metricset="load",module="system"} 2
+# HELP metricbeat_metricbeat_system_cpu system.cpu (deprecated, use metricbeat_metricset_*)
+# TYPE metricbeat_metricbeat_system_cpu counter
+metricbeat_metricbeat_system_cpu{event="failures"} 0
+metricbeat_metricbeat_system_cpu{event="success"} 360
+# HELP metricbeat_metricbeat_system_load system.load (deprecated, use metricbeat_metricset_*)
+# TYPE metricbeat_metricbeat_system_load counter
+metricbeat_metricbeat_system_load{event="failures"} 2
+metricbeat_metricbeat_system_load{event="success"} 356
+# HELP metricbeat_metricbeat_system_memory system.memory (deprecated, use metricbeat_metricset_*)
+# TYPE metricbeat_metricbeat_system_memory counter
+metricbeat_metricbeat_system_memory{event="failures"} 0
+metricbeat_metricbeat_system_memory{event="success"} 0
+`
+
+	if err := testutil.CollectAndCompare(c, strings.NewReader(expected),
+		"metricbeat_metricset_events",
+		"metricbeat_metricset_success",
+		"metricbeat_metricset_failures",
+		"metricbeat_metricbeat_system_cpu",
+		"metricbeat_metricbeat_system_load",
+		"metricbeat_metricbeat_system_memory",
+	); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,111 @@
+package collector
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+//OsqueryQuery json structure of the counters of a scheduled query
+type OsqueryQuery struct {
+	Runs     float64 `json:"runs"`
+	Errors   float64 `json:"errors"`
+	Results  float64 `json:"results"`
+	Duration struct {
+		Histogram Histogram `json:"histogram"`
+	} `json:"duration"`
+}
+
+//Osquerybeat json structure
+type Osquerybeat struct {
+	Osqueryd struct {
+		Running  float64 `json:"running"`
+		Restarts float64 `json:"restarts"`
+	} `json:"osqueryd"`
+	Queries map[string]OsqueryQuery `json:"queries"`
+}
+
+type osquerybeatCollector struct {
+	beatInfo *BeatInfo
+	stats    *Stats
+	metrics  exportedMetrics
+	runs     *prometheus.Desc
+	errors   *prometheus.Desc
+	results  *prometheus.Desc
+	duration *prometheus.Desc
+}
+
+// NewOsquerybeatCollector constructor
+func NewOsquerybeatCollector(beatInfo *BeatInfo, stats *Stats) prometheus.Collector {
+	return &osquerybeatCollector{
+		beatInfo: beatInfo,
+		stats:    stats,
+		metrics: exportedMetrics{
+			{
+				desc: prometheus.NewDesc(
+					prometheus.BuildFQName(beatInfo.Beat, "osqueryd", "running"),
+					"osquerybeat.osqueryd.running",
+					nil, nil,
+				),
+				eval:    func(stats *Stats) float64 { return stats.Osquerybeat.Osqueryd.Running },
+				valType: prometheus.GaugeValue,
+			},
+			{
+				desc: prometheus.NewDesc(
+					prometheus.BuildFQName(beatInfo.Beat, "osqueryd", "restarts_total"),
+					"osquerybeat.osqueryd.restarts",
+					nil, nil,
+				),
+				eval:    func(stats *Stats) float64 { return stats.Osquerybeat.Osqueryd.Restarts },
+				valType: prometheus.CounterValue,
+			},
+		},
+		runs: prometheus.NewDesc(
+			prometheus.BuildFQName(beatInfo.Beat, "query", "runs_total"),
+			"osquerybeat.queries.<name>.runs",
+			[]string{"query"}, nil,
+		),
+		errors: prometheus.NewDesc(
+			prometheus.BuildFQName(beatInfo.Beat, "query", "errors_total"),
+			"osquerybeat.queries.<name>.errors",
+			[]string{"query"}, nil,
+		),
+		results: prometheus.NewDesc(
+			prometheus.BuildFQName(beatInfo.Beat, "query", "results_total"),
+			"osquerybeat.queries.<name>.results",
+			[]string{"query"}, nil,
+		),
+		duration: prometheus.NewDesc(
+			prometheus.BuildFQName(beatInfo.Beat, "query", "duration_milliseconds"),
+			"osquerybeat.queries.<name>.duration",
+			[]string{"query"}, nil,
+		),
+	}
+}
+
+// Describe returns all descriptions of the collector.
+func (c *osquerybeatCollector) Describe(ch chan<- *prometheus.Desc) {
+
+	for _, metric := range c.metrics {
+		ch <- metric.desc
+	}
+	ch <- c.runs
+	ch <- c.errors
+	ch <- c.results
+	ch <- c.duration
+
+}
+
+// Collect returns the current state of all metrics of the collector.
+func (c *osquerybeatCollector) Collect(ch chan<- prometheus.Metric) {
+
+	for _, i := range c.metrics {
+		ch <- prometheus.MustNewConstMetric(i.desc, i.valType, i.eval(c.stats))
+	}
+
+	for name, query := range c.stats.Osquerybeat.Queries {
+		ch <- prometheus.MustNewConstMetric(c.runs, prometheus.CounterValue, query.Runs, name)
+		ch <- prometheus.MustNewConstMetric(c.errors, prometheus.CounterValue, query.Errors, name)
+		ch <- prometheus.MustNewConstMetric(c.results, prometheus.CounterValue, query.Results, name)
+		ch <- newHistogramSummary(c.duration, query.Duration.Histogram, name)
+	}
+
+}
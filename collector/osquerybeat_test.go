@@ -0,0 +1,53 @@
+package collector
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestOsquerybeatCollector(t *testing.T) {
+	c := NewOsquerybeatCollector(&BeatInfo{Beat: "osquerybeat"}, loadStats(t, "testdata/osquerybeat/stats.json"))
+
+	expected := `
+# HELP osquerybeat_osqueryd_running osquerybeat.osqueryd.running
+# TYPE osquerybeat_osqueryd_running gauge
+osquerybeat_osqueryd_running 1
+# HELP osquerybeat_osqueryd_restarts_total osquerybeat.osqueryd.restarts
+# TYPE osquerybeat_osqueryd_restarts_total counter
+osquerybeat_osqueryd_restarts_total 1
+# HELP osquerybeat_query_runs_total osquerybeat.queries.<name>.runs
+# TYPE osquerybeat_query_runs_total counter
+osquerybeat_query_runs_total{query="listening_ports"} 60
+osquerybeat_query_runs_total{query="processes"} 120
+# HELP osquerybeat_query_errors_total osquerybeat.queries.<name>.errors
+# TYPE osquerybeat_query_errors_total counter
+osquerybeat_query_errors_total{query="listening_ports"} 0
+osquerybeat_query_errors_total{query="processes"} 2
+# HELP osquerybeat_query_results_total osquerybeat.queries.<name>.results
+# TYPE osquerybeat_query_results_total counter
+osquerybeat_query_results_total{query="listening_ports"} 1320
+osquerybeat_query_results_total{query="processes"} 13200
+# HELP osquerybeat_query_duration_milliseconds osquerybeat.queries.<name>.duration
+# TYPE osquerybeat_query_duration_milliseconds summary
+osquerybeat_query_duration_milliseconds{query="listening_ports",quantile="0.5"} 7
+osquerybeat_query_duration_milliseconds{query="listening_ports",quantile="0.75"} 9
+osquerybeat_query_duration_milliseconds{query="listening_ports",quantile="0.95"} 14
+osquerybeat_query_duration_milliseconds{query="listening_ports",quantile="0.99"} 28
+osquerybeat_query_duration_milliseconds{query="listening_ports",quantile="0.999"} 31
+osquerybeat_query_duration_milliseconds_sum{query="listening_ports"} 480
+osquerybeat_query_duration_milliseconds_count{query="listening_ports"} 60
+osquerybeat_query_duration_milliseconds{query="processes",quantile="0.5"} 38
+osquerybeat_query_duration_milliseconds{query="processes",quantile="0.75"} 47
+osquerybeat_query_duration_milliseconds{query="processes",quantile="0.95"} 88
+osquerybeat_query_duration_milliseconds{query="processes",quantile="0.99"} 240
+osquerybeat_query_duration_milliseconds{query="processes",quantile="0.999"} 310
+osquerybeat_query_duration_milliseconds_sum{query="processes"} 5100
+osquerybeat_query_duration_milliseconds_count{query="processes"} 120
+`
+
+	if err := testutil.CollectAndCompare(c, strings.NewReader(expected)); err != nil {
+		t.Fatal(err)
+	}
+}
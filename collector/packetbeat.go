@@ -0,0 +1,137 @@
+package collector
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+//PacketbeatProtocol json structure of the counters of a protocol analyzer
+type PacketbeatProtocol struct {
+	Transactions       float64 `json:"transactions"`
+	UnmatchedRequests  float64 `json:"unmatched_requests"`
+	UnmatchedResponses float64 `json:"unmatched_responses"`
+}
+
+//Packetbeat json structure, packetbeat reports its sections at the top level of the stats
+type Packetbeat struct {
+	AMQP      PacketbeatProtocol `json:"amqp"`
+	Cassandra PacketbeatProtocol `json:"cassandra"`
+	DNS       PacketbeatProtocol `json:"dns"`
+	HTTP      PacketbeatProtocol `json:"http"`
+	Memcache  PacketbeatProtocol `json:"memcache"`
+	MongoDB   PacketbeatProtocol `json:"mongodb"`
+	MySQL     PacketbeatProtocol `json:"mysql"`
+	PgSQL     PacketbeatProtocol `json:"pgsql"`
+	Redis     PacketbeatProtocol `json:"redis"`
+	SIP       PacketbeatProtocol `json:"sip"`
+	Thrift    PacketbeatProtocol `json:"thrift"`
+
+	TCP struct {
+		DroppedBecauseOfGaps float64 `json:"dropped_because_of_gaps"`
+	} `json:"tcp"`
+	Flows struct {
+		Active float64 `json:"active"`
+	} `json:"flows"`
+}
+
+// packetbeatProtocols maps the protocol label to the counters of its analyzer
+var packetbeatProtocols = map[string]func(p *Packetbeat) *PacketbeatProtocol{
+	"amqp":      func(p *Packetbeat) *PacketbeatProtocol { return &p.AMQP },
+	"cassandra": func(p *Packetbeat) *PacketbeatProtocol { return &p.Cassandra },
+	"dns":       func(p *Packetbeat) *PacketbeatProtocol { return &p.DNS },
+	"http":      func(p *Packetbeat) *PacketbeatProtocol { return &p.HTTP },
+	"memcache":  func(p *Packetbeat) *PacketbeatProtocol { return &p.Memcache },
+	"mongodb":   func(p *Packetbeat) *PacketbeatProtocol { return &p.MongoDB },
+	"mysql":     func(p *Packetbeat) *PacketbeatProtocol { return &p.MySQL },
+	"pgsql":     func(p *Packetbeat) *PacketbeatProtocol { return &p.PgSQL },
+	"redis":     func(p *Packetbeat) *PacketbeatProtocol { return &p.Redis },
+	"sip":       func(p *Packetbeat) *PacketbeatProtocol { return &p.SIP },
+	"thrift":    func(p *Packetbeat) *PacketbeatProtocol { return &p.Thrift },
+}
+
+type packetbeatCollector struct {
+	beatInfo *BeatInfo
+	stats    *Stats
+	metrics  exportedMetrics
+}
+
+// NewPacketbeatCollector constructor
+func NewPacketbeatCollector(beatInfo *BeatInfo, stats *Stats) prometheus.Collector {
+	metrics := exportedMetrics{
+		{
+			desc: prometheus.NewDesc(
+				prometheus.BuildFQName(beatInfo.Beat, "tcp", "dropped_because_of_gaps"),
+				"tcp.dropped_because_of_gaps",
+				nil, nil,
+			),
+			eval:    func(stats *Stats) float64 { return stats.Packetbeat.TCP.DroppedBecauseOfGaps },
+			valType: prometheus.CounterValue,
+		},
+		{
+			desc: prometheus.NewDesc(
+				prometheus.BuildFQName(beatInfo.Beat, "flows", "active"),
+				"flows.active",
+				nil, nil,
+			),
+			eval:    func(stats *Stats) float64 { return stats.Packetbeat.Flows.Active },
+			valType: prometheus.GaugeValue,
+		},
+	}
+
+	for name, protocol := range packetbeatProtocols {
+		protocol := protocol
+
+		metrics = append(metrics, exportedMetrics{
+			{
+				desc: prometheus.NewDesc(
+					prometheus.BuildFQName(beatInfo.Beat, "protocol", "transactions"),
+					"<protocol>.transactions",
+					nil, prometheus.Labels{"protocol": name},
+				),
+				eval:    func(stats *Stats) float64 { return protocol(&stats.Packetbeat).Transactions },
+				valType: prometheus.CounterValue,
+			},
+			{
+				desc: prometheus.NewDesc(
+					prometheus.BuildFQName(beatInfo.Beat, "protocol", "unmatched_requests"),
+					"<protocol>.unmatched_requests",
+					nil, prometheus.Labels{"protocol": name},
+				),
+				eval:    func(stats *Stats) float64 { return protocol(&stats.Packetbeat).UnmatchedRequests },
+				valType: prometheus.CounterValue,
+			},
+			{
+				desc: prometheus.NewDesc(
+					prometheus.BuildFQName(beatInfo.Beat, "protocol", "unmatched_responses"),
+					"<protocol>.unmatched_responses",
+					nil, prometheus.Labels{"protocol": name},
+				),
+				eval:    func(stats *Stats) float64 { return protocol(&stats.Packetbeat).UnmatchedResponses },
+				valType: prometheus.CounterValue,
+			},
+		}...)
+	}
+
+	return &packetbeatCollector{
+		beatInfo: beatInfo,
+		stats:    stats,
+		metrics:  metrics,
+	}
+}
+
+// Describe returns all descriptions of the collector.
+func (c *packetbeatCollector) Describe(ch chan<- *prometheus.Desc) {
+
+	for _, metric := range c.metrics {
+		ch <- metric.desc
+	}
+
+}
+
+// Collect returns the current state of all metrics of the collector.
+func (c *packetbeatCollector) Collect(ch chan<- prometheus.Metric) {
+
+	for _, i := range c.metrics {
+		ch <- prometheus.MustNewConstMetric(i.desc, i.valType, i.eval(c.stats))
+	}
+
+}
@@ -0,0 +1,86 @@
+//go:build linux
+// +build linux
+
+package collector
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/procfs"
+	"golang.org/x/sys/unix"
+)
+
+// pid resolves the PID of the beat, it is looked up on every call as the beat may have been restarted
+func (p beatProcess) pid() (int, error) {
+	if p.knownPID != 0 {
+		return p.knownPID, nil
+	}
+
+	if p.pidFile != "" {
+		content, err := ioutil.ReadFile(p.pidFile)
+		if err != nil {
+			return 0, err
+		}
+
+		return strconv.Atoi(strings.TrimSpace(string(content)))
+	}
+
+	if p.socketPath != "" {
+		return unixSocketPeerPID(p.socketPath)
+	}
+
+	procs, err := procfs.AllProcs()
+	if err != nil {
+		return 0, err
+	}
+
+	// AllProcs is sorted by PID, the first match is the longest running one
+	for _, proc := range procs {
+		comm, err := proc.Comm()
+		if err != nil {
+			continue
+		}
+
+		// beat info names have dashes removed, apm-server is reported as apmserver
+		if comm == p.name || strings.ReplaceAll(comm, "-", "") == p.name {
+			return proc.PID, nil
+		}
+	}
+
+	return 0, fmt.Errorf("no process named %q found", p.name)
+}
+
+// unixSocketPeerPID returns the PID of the process listening on a unix socket
+func unixSocketPeerPID(path string) (int, error) {
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	raw, err := conn.(*net.UnixConn).SyscallConn()
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		cred    *unix.Ucred
+		credErr error
+	)
+
+	err = raw.Control(func(fd uintptr) {
+		cred, credErr = unix.GetsockoptUcred(int(fd), unix.SOL_SOCKET, unix.SO_PEERCRED)
+	})
+	if err != nil {
+		return 0, err
+	}
+	if credErr != nil {
+		return 0, credErr
+	}
+
+	return int(cred.Pid), nil
+}
@@ -0,0 +1,10 @@
+package collector
+
+// beatProcess tells how to find the PID of the beat: a known PID, its pidfile, the peer of its
+// unix socket, or by process name, in that order
+type beatProcess struct {
+	knownPID   int
+	pidFile    string
+	socketPath string
+	name       string
+}
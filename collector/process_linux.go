@@ -0,0 +1,145 @@
+//go:build linux
+// +build linux
+
+package collector
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/procfs"
+	log "github.com/sirupsen/logrus"
+)
+
+type processCollector struct {
+	beatInfo        *BeatInfo
+	process         beatProcess
+	cpuTime         *prometheus.Desc
+	residentMemory  *prometheus.Desc
+	threads         *prometheus.Desc
+	openFDs         *prometheus.Desc
+	maxFDs          *prometheus.Desc
+	contextSwitches *prometheus.Desc
+	ioBytes         *prometheus.Desc
+	ioChars         *prometheus.Desc
+	ioSyscalls      *prometheus.Desc
+}
+
+// NewProcessCollector constructor
+func NewProcessCollector(beatInfo *BeatInfo, process beatProcess) prometheus.Collector {
+	return &processCollector{
+		beatInfo: beatInfo,
+		process:  process,
+		cpuTime: prometheus.NewDesc(
+			prometheus.BuildFQName(beatInfo.Beat, "process", "cpu_seconds_total"),
+			"/proc/<pid>/stat utime + stime",
+			nil, nil,
+		),
+		residentMemory: prometheus.NewDesc(
+			prometheus.BuildFQName(beatInfo.Beat, "process", "resident_memory_bytes"),
+			"/proc/<pid>/stat rss",
+			nil, nil,
+		),
+		threads: prometheus.NewDesc(
+			prometheus.BuildFQName(beatInfo.Beat, "process", "threads"),
+			"/proc/<pid>/stat num_threads",
+			nil, nil,
+		),
+		openFDs: prometheus.NewDesc(
+			prometheus.BuildFQName(beatInfo.Beat, "process", "open_fds"),
+			"/proc/<pid>/fd",
+			nil, nil,
+		),
+		maxFDs: prometheus.NewDesc(
+			prometheus.BuildFQName(beatInfo.Beat, "process", "max_fds"),
+			"/proc/<pid>/limits max open files",
+			nil, nil,
+		),
+		contextSwitches: prometheus.NewDesc(
+			prometheus.BuildFQName(beatInfo.Beat, "process", "context_switches_total"),
+			"/proc/<pid>/status ctxt_switches",
+			[]string{"type"}, nil,
+		),
+		ioBytes: prometheus.NewDesc(
+			prometheus.BuildFQName(beatInfo.Beat, "process", "io_bytes_total"),
+			"/proc/<pid>/io read_bytes and write_bytes",
+			[]string{"direction"}, nil,
+		),
+		ioChars: prometheus.NewDesc(
+			prometheus.BuildFQName(beatInfo.Beat, "process", "io_chars_total"),
+			"/proc/<pid>/io rchar and wchar",
+			[]string{"direction"}, nil,
+		),
+		ioSyscalls: prometheus.NewDesc(
+			prometheus.BuildFQName(beatInfo.Beat, "process", "io_syscalls_total"),
+			"/proc/<pid>/io syscr and syscw",
+			[]string{"direction"}, nil,
+		),
+	}
+}
+
+// Describe returns all descriptions of the collector.
+func (c *processCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.cpuTime
+	ch <- c.residentMemory
+	ch <- c.threads
+	ch <- c.openFDs
+	ch <- c.maxFDs
+	ch <- c.contextSwitches
+	ch <- c.ioBytes
+	ch <- c.ioChars
+	ch <- c.ioSyscalls
+}
+
+// Collect returns the current state of all metrics of the collector.
+func (c *processCollector) Collect(ch chan<- prometheus.Metric) {
+
+	pid, err := c.process.pid()
+	if err != nil {
+		log.Errorf("Could not find beat process: %v", err)
+		return
+	}
+
+	proc, err := procfs.NewProc(pid)
+	if err != nil {
+		log.Errorf("Could not open beat process %d: %v", pid, err)
+		return
+	}
+
+	// each file is reported on its own, some of them are only readable by the beat user or root
+	if stat, err := proc.Stat(); err == nil {
+		ch <- prometheus.MustNewConstMetric(c.cpuTime, prometheus.CounterValue, stat.CPUTime())
+		ch <- prometheus.MustNewConstMetric(c.residentMemory, prometheus.GaugeValue, float64(stat.ResidentMemory()))
+		ch <- prometheus.MustNewConstMetric(c.threads, prometheus.GaugeValue, float64(stat.NumThreads))
+	} else {
+		log.Errorf("Could not read stat of beat process %d: %v", pid, err)
+	}
+
+	if fds, err := proc.FileDescriptorsLen(); err == nil {
+		ch <- prometheus.MustNewConstMetric(c.openFDs, prometheus.GaugeValue, float64(fds))
+	} else {
+		log.Errorf("Could not read file descriptors of beat process %d: %v", pid, err)
+	}
+
+	if limits, err := proc.Limits(); err == nil {
+		ch <- prometheus.MustNewConstMetric(c.maxFDs, prometheus.GaugeValue, float64(limits.OpenFiles))
+	} else {
+		log.Errorf("Could not read limits of beat process %d: %v", pid, err)
+	}
+
+	if status, err := proc.NewStatus(); err == nil {
+		ch <- prometheus.MustNewConstMetric(c.contextSwitches, prometheus.CounterValue, float64(status.VoluntaryCtxtSwitches), "voluntary")
+		ch <- prometheus.MustNewConstMetric(c.contextSwitches, prometheus.CounterValue, float64(status.NonVoluntaryCtxtSwitches), "involuntary")
+	} else {
+		log.Errorf("Could not read status of beat process %d: %v", pid, err)
+	}
+
+	if io, err := proc.IO(); err == nil {
+		ch <- prometheus.MustNewConstMetric(c.ioBytes, prometheus.CounterValue, float64(io.ReadBytes), "read")
+		ch <- prometheus.MustNewConstMetric(c.ioBytes, prometheus.CounterValue, float64(io.WriteBytes), "write")
+		ch <- prometheus.MustNewConstMetric(c.ioChars, prometheus.CounterValue, float64(io.RChar), "read")
+		ch <- prometheus.MustNewConstMetric(c.ioChars, prometheus.CounterValue, float64(io.WChar), "write")
+		ch <- prometheus.MustNewConstMetric(c.ioSyscalls, prometheus.CounterValue, float64(io.SyscR), "read")
+		ch <- prometheus.MustNewConstMetric(c.ioSyscalls, prometheus.CounterValue, float64(io.SyscW), "write")
+	} else {
+		log.Errorf("Could not read io of beat process %d: %v", pid, err)
+	}
+}
@@ -0,0 +1,25 @@
+//go:build !linux
+// +build !linux
+
+package collector
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+)
+
+type processCollector struct{}
+
+// NewProcessCollector constructor, process metrics are only read on linux
+func NewProcessCollector(beatInfo *BeatInfo, process beatProcess) prometheus.Collector {
+	return &processCollector{}
+}
+
+// Describe returns all descriptions of the collector.
+func (c *processCollector) Describe(ch chan<- *prometheus.Desc) {
+}
+
+// Collect returns the current state of all metrics of the collector.
+func (c *processCollector) Collect(ch chan<- prometheus.Metric) {
+	log.Debug("Process metrics are only read on linux")
+}
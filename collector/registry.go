@@ -0,0 +1,517 @@
+package collector
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+)
+
+// registryOtherPath is the path label of files beyond the registry cardinality cap
+const registryOtherPath = "other"
+
+// registryValue json structure of a filebeat registry entry, covering both log and filestream inputs
+type registryValue struct {
+	Source      string  `json:"source"`
+	Offset      int64   `json:"offset"`
+	TTL         int64   `json:"ttl"`
+	Timestamp   []int64 `json:"timestamp"`
+	Updated     []int64 `json:"updated"`
+	FileStateOS struct {
+		Inode uint64 `json:"inode"`
+	} `json:"FileStateOS"`
+
+	Cursor struct {
+		Offset int64 `json:"offset"`
+	} `json:"cursor"`
+	Meta struct {
+		Source string `json:"source"`
+	} `json:"meta"`
+}
+
+// registryEntry is the state filebeat keeps for a single file
+type registryEntry struct {
+	Source  string
+	Offset  int64
+	Inode   uint64
+	TTL     time.Duration
+	Updated time.Time
+}
+
+// expired reports whether clean_inactive would have removed the entry, a negative TTL disables expiry
+func (e registryEntry) expired(now time.Time) bool {
+	return e.TTL > 0 && !e.Updated.IsZero() && e.Updated.Add(e.TTL).Before(now)
+}
+
+// registryState is the content of the filebeat registry after replaying its log
+type registryState struct {
+	Entries        map[string]registryEntry
+	CheckpointSize int64
+	CheckpointTime time.Time
+	LogSize        int64
+}
+
+// RegistryReader parses the filebeat registry of a data path and keeps the result until one of the registry
+// files changes. The registry and unharvested collectors share one, registries grow to hundreds of MB.
+type RegistryReader struct {
+	dir   string
+	mu    sync.Mutex
+	state *registryState
+	stamp [3]registryFileStamp
+}
+
+// registryFileStamp identifies the version of a registry file
+type registryFileStamp struct {
+	path    string
+	size    int64
+	modTime int64
+}
+
+// NewRegistryReader constructor
+func NewRegistryReader(dataPath string) *RegistryReader {
+	return &RegistryReader{
+		dir: filepath.Join(dataPath, "registry", "filebeat"),
+	}
+}
+
+// read returns the registry state, parsing the registry again only when active.dat, the checkpoint or log.json changed
+func (r *RegistryReader) read() (*registryState, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stamp := r.currentStamp()
+	if r.state != nil && stamp == r.stamp {
+		return r.state, nil
+	}
+
+	state, err := readRegistry(r.dir)
+	if err != nil {
+		r.state = nil
+		return nil, err
+	}
+
+	r.state, r.stamp = state, stamp
+
+	return state, nil
+}
+
+func (r *RegistryReader) currentStamp() [3]registryFileStamp {
+	checkpoint, _ := registryCheckpoint(r.dir)
+
+	var stamp [3]registryFileStamp
+
+	for i, path := range []string{filepath.Join(r.dir, "active.dat"), checkpoint, filepath.Join(r.dir, "log.json")} {
+		stamp[i].path = path
+
+		if fi, err := os.Stat(path); err == nil {
+			stamp[i].size = fi.Size()
+			stamp[i].modTime = fi.ModTime().UnixNano()
+		}
+	}
+
+	return stamp
+}
+
+// readRegistry loads the active checkpoint of a filebeat registry and applies the operations of log.json on top of it
+func readRegistry(dir string) (*registryState, error) {
+	state := &registryState{
+		Entries: make(map[string]registryEntry),
+	}
+
+	// filebeat truncates log.json whenever it writes a new checkpoint, replaying the log without one
+	// would miss nearly every entry, so a checkpoint replaced while reading is resolved once more
+	for attempt := 0; ; attempt++ {
+		err := state.loadCheckpoint(dir)
+		if os.IsNotExist(err) && attempt == 0 {
+			state.Entries = make(map[string]registryEntry)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		break
+	}
+
+	logPath := filepath.Join(dir, "log.json")
+
+	if fi, err := os.Stat(logPath); err == nil {
+		state.LogSize = fi.Size()
+	}
+
+	if err := state.readLog(logPath); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	return state, nil
+}
+
+// loadCheckpoint reads the current checkpoint of the registry into the state, if there is one
+func (s *registryState) loadCheckpoint(dir string) error {
+	checkpoint, err := registryCheckpoint(dir)
+	if err != nil || checkpoint == "" {
+		return err
+	}
+
+	fi, err := os.Stat(checkpoint)
+	if err != nil {
+		return err
+	}
+
+	s.CheckpointSize = fi.Size()
+	s.CheckpointTime = fi.ModTime()
+
+	return s.readCheckpoint(checkpoint)
+}
+
+// registryCheckpoint returns the checkpoint file referenced by active.dat, falling back to the newest <id>.json file
+// when there is no active.dat or the file it references is already gone
+func registryCheckpoint(dir string) (string, error) {
+	var referenced string
+
+	active, err := ioutil.ReadFile(filepath.Join(dir, "active.dat"))
+	if err == nil && len(strings.TrimSpace(string(active))) > 0 {
+		// active.dat holds the path filebeat wrote, which may differ when the data path is mounted elsewhere
+		referenced = filepath.Join(dir, filepath.Base(strings.TrimSpace(string(active))))
+
+		if _, err := os.Stat(referenced); err == nil {
+			return referenced, nil
+		}
+	}
+
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+
+	var (
+		newest   string
+		newestID int64 = -1
+	)
+
+	for _, f := range files {
+		id, err := strconv.ParseInt(strings.TrimSuffix(f.Name(), ".json"), 10, 64)
+		if err != nil || !strings.HasSuffix(f.Name(), ".json") {
+			continue
+		}
+
+		if id > newestID {
+			newestID = id
+			newest = filepath.Join(dir, f.Name())
+		}
+	}
+
+	if newest == "" && referenced != "" {
+		return "", fmt.Errorf("checkpoint %s referenced by active.dat does not exist", referenced)
+	}
+
+	return newest, nil
+}
+
+func (s *registryState) readCheckpoint(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var entries []json.RawMessage
+
+	if err := json.NewDecoder(f).Decode(&entries); err != nil {
+		return err
+	}
+
+	for _, raw := range entries {
+		var key struct {
+			Key string `json:"_key"`
+		}
+
+		if err := json.Unmarshal(raw, &key); err != nil {
+			return err
+		}
+
+		s.set(key.Key, raw)
+	}
+
+	return nil
+}
+
+func (s *registryState) readLog(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	decoder := json.NewDecoder(f)
+
+	for {
+		var op struct {
+			Op string `json:"op"`
+		}
+		var data struct {
+			K string          `json:"k"`
+			V json.RawMessage `json:"v"`
+		}
+
+		if err := decoder.Decode(&op); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+
+		if err := decoder.Decode(&data); err != nil {
+			// filebeat may be in the middle of appending an operation
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				return nil
+			}
+			return err
+		}
+
+		switch op.Op {
+		case "set":
+			s.set(data.K, data.V)
+		case "remove":
+			delete(s.Entries, data.K)
+		}
+	}
+}
+
+func (s *registryState) set(key string, raw json.RawMessage) {
+	var value registryValue
+
+	if err := json.Unmarshal(raw, &value); err != nil {
+		log.Debugf("Skipping registry entry %q: %v", key, err)
+		return
+	}
+
+	entry := registryEntry{
+		Source:  value.Source,
+		Offset:  value.Offset,
+		Inode:   value.FileStateOS.Inode,
+		TTL:     time.Duration(value.TTL),
+		Updated: registryTime(value.Timestamp),
+	}
+
+	// filestream keeps its state in cursor/meta and encodes the file identity in the key
+	if entry.Source == "" {
+		entry.Source = value.Meta.Source
+		entry.Offset = value.Cursor.Offset
+		entry.Inode = registryKeyInode(key)
+		entry.Updated = registryTime(value.Updated)
+	}
+
+	s.Entries[key] = entry
+}
+
+// registryKeyInode extracts the inode from keys like filestream::<id>::native::<inode>-<device>
+func registryKeyInode(key string) uint64 {
+	parts := strings.Split(key, "::")
+	if len(parts) < 2 || parts[len(parts)-2] != "native" {
+		return 0
+	}
+
+	inode, err := strconv.ParseUint(strings.SplitN(parts[len(parts)-1], "-", 2)[0], 10, 64)
+	if err != nil {
+		return 0
+	}
+
+	return inode
+}
+
+// registryTime decodes the [ext, sec] time encoding of the registry, the second value holds unix seconds
+func registryTime(encoded []int64) time.Time {
+	if len(encoded) != 2 || encoded[1] == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(encoded[1], 0)
+}
+
+type registryCollector struct {
+	beatInfo       *BeatInfo
+	registry       *RegistryReader
+	maxFiles       int
+	pathGroups     []string
+	lag            *prometheus.Desc
+	fileSize       *prometheus.Desc
+	entries        *prometheus.Desc
+	checkpointAge  *prometheus.Desc
+	staleEntries   *prometheus.Desc
+	expiredEntries *prometheus.Desc
+}
+
+// NewRegistryCollector constructor
+func NewRegistryCollector(beatInfo *BeatInfo, registry *RegistryReader, maxFiles int, pathGroups []string) prometheus.Collector {
+	return &registryCollector{
+		beatInfo:   beatInfo,
+		registry:   registry,
+		maxFiles:   maxFiles,
+		pathGroups: pathGroups,
+		lag: prometheus.NewDesc(
+			prometheus.BuildFQName(beatInfo.Beat, "registry", "file_lag_bytes"),
+			"bytes of the file on disk not yet read by filebeat",
+			[]string{"path"}, nil,
+		),
+		fileSize: prometheus.NewDesc(
+			prometheus.BuildFQName(beatInfo.Beat, "registry", "file_size_bytes"),
+			"size of the registry files",
+			[]string{"file"}, nil,
+		),
+		entries: prometheus.NewDesc(
+			prometheus.BuildFQName(beatInfo.Beat, "registry", "entries"),
+			"number of entries in the registry",
+			nil, nil,
+		),
+		checkpointAge: prometheus.NewDesc(
+			prometheus.BuildFQName(beatInfo.Beat, "registry", "checkpoint_age_seconds"),
+			"time since the registry was last checkpointed",
+			nil, nil,
+		),
+		staleEntries: prometheus.NewDesc(
+			prometheus.BuildFQName(beatInfo.Beat, "registry", "stale_entries"),
+			"registry entries whose source file no longer exists",
+			nil, nil,
+		),
+		expiredEntries: prometheus.NewDesc(
+			prometheus.BuildFQName(beatInfo.Beat, "registry", "expired_entries"),
+			"registry entries whose ttl has expired",
+			nil, nil,
+		),
+	}
+}
+
+// Describe returns all descriptions of the collector.
+func (c *registryCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.lag
+	ch <- c.fileSize
+	ch <- c.entries
+	ch <- c.checkpointAge
+	ch <- c.staleEntries
+	ch <- c.expiredEntries
+}
+
+// Collect returns the current state of all metrics of the collector.
+func (c *registryCollector) Collect(ch chan<- prometheus.Metric) {
+
+	state, err := c.registry.read()
+	if err != nil {
+		log.Errorf("Could not read filebeat registry: %v", err)
+		return
+	}
+
+	lag, stale := c.inspectFiles(state)
+
+	for path, behind := range lag {
+		ch <- prometheus.MustNewConstMetric(c.lag, prometheus.GaugeValue, behind, path)
+	}
+
+	var (
+		now     = time.Now()
+		expired float64
+	)
+
+	for _, entry := range state.Entries {
+		if entry.expired(now) {
+			expired++
+		}
+	}
+
+
+	ch <- prometheus.MustNewConstMetric(c.fileSize, prometheus.GaugeValue, float64(state.CheckpointSize), "checkpoint")
+	ch <- prometheus.MustNewConstMetric(c.fileSize, prometheus.GaugeValue, float64(state.LogSize), "log")
+	ch <- prometheus.MustNewConstMetric(c.entries, prometheus.GaugeValue, float64(len(state.Entries)))
+	// without a checkpoint there is no age, exporting 0 would look like a fresh checkpoint
+	if !state.CheckpointTime.IsZero() {
+		ch <- prometheus.MustNewConstMetric(c.checkpointAge, prometheus.GaugeValue, now.Sub(state.CheckpointTime).Seconds())
+	}
+	ch <- prometheus.MustNewConstMetric(c.staleEntries, prometheus.GaugeValue, stale)
+	ch <- prometheus.MustNewConstMetric(c.expiredEntries, prometheus.GaugeValue, expired)
+
+}
+
+// inspectFiles returns the bytes behind per path label, grouped by pattern and capped to maxFiles labels,
+// and the number of entries whose source file is gone
+func (c *registryCollector) inspectFiles(state *registryState) (map[string]float64, float64) {
+	var (
+		lag   = make(map[string]float64)
+		stale float64
+	)
+
+	for _, entry := range state.Entries {
+		if entry.Source == "" {
+			continue
+		}
+
+		fi, err := os.Stat(entry.Source)
+		if os.IsNotExist(err) {
+			stale++
+			continue
+		}
+		if err != nil {
+			continue
+		}
+
+		// the path now belongs to a different file, the entry is for a rotated file
+		if inode, ok := fileInode(fi); ok && entry.Inode != 0 && inode != entry.Inode {
+			continue
+		}
+
+		behind := fi.Size() - entry.Offset
+		if behind < 0 {
+			// file was truncated, filebeat will start over
+			behind = fi.Size()
+		}
+
+		lag[c.pathLabel(entry.Source)] += float64(behind)
+	}
+
+	if c.maxFiles <= 0 || len(lag) <= c.maxFiles {
+		return lag, stale
+	}
+
+	paths := make([]string, 0, len(lag))
+	for path := range lag {
+		paths = append(paths, path)
+	}
+
+	// keep the files furthest behind, everything else is summed up. Ties are ordered by path, so the same
+	// files end up in "other" on every scrape
+	sort.Slice(paths, func(i, j int) bool {
+		if lag[paths[i]] != lag[paths[j]] {
+			return lag[paths[i]] > lag[paths[j]]
+		}
+		return paths[i] < paths[j]
+	})
+
+	capped := make(map[string]float64, c.maxFiles)
+	for i, path := range paths {
+		if i < c.maxFiles-1 {
+			capped[path] = lag[path]
+			continue
+		}
+		capped[registryOtherPath] += lag[path]
+	}
+
+	return capped, stale
+}
+
+func (c *registryCollector) pathLabel(source string) string {
+	for _, pattern := range c.pathGroups {
+		if ok, _ := filepath.Match(pattern, source); ok {
+			return pattern
+		}
+	}
+
+	return source
+}
@@ -0,0 +1,180 @@
+package collector
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// writeRegistry creates a data path with a filebeat registry holding the given files
+func writeRegistry(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "registry")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	registryDir := filepath.Join(dir, "registry", "filebeat")
+	if err := os.MkdirAll(registryDir, 0755); err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(registryDir, name), []byte(content), 0644); err != nil {
+			os.RemoveAll(dir)
+			t.Fatal(err)
+		}
+	}
+
+	return dir
+}
+
+func TestRegistryCapTies(t *testing.T) {
+	dir := writeRegistry(t, nil)
+	defer os.RemoveAll(dir)
+
+	var registryLog strings.Builder
+	for i, name := range []string{"d.log", "c.log", "b.log", "a.log"} {
+		path := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(path, []byte("0123456789"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		fmt.Fprintf(&registryLog, "{\"op\":\"set\",\"id\":%d}\n{\"k\":\"filebeat::logs::native::%d-1\",\"v\":{\"source\":%q,\"offset\":0}}\n", i+1, i+1, path)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "registry", "filebeat", "log.json"), []byte(registryLog.String()), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := fmt.Sprintf(`
+# HELP filebeat_registry_file_lag_bytes bytes of the file on disk not yet read by filebeat
+# TYPE filebeat_registry_file_lag_bytes gauge
+filebeat_registry_file_lag_bytes{path=%q} 10
+filebeat_registry_file_lag_bytes{path=%q} 10
+filebeat_registry_file_lag_bytes{path="other"} 20
+`, filepath.Join(dir, "a.log"), filepath.Join(dir, "b.log"))
+
+	// equal lag must not shuffle files in and out of "other" between scrapes
+	for i := 0; i < 10; i++ {
+		c := NewRegistryCollector(&BeatInfo{Beat: "filebeat"}, NewRegistryReader(dir), 3, nil)
+
+		if err := testutil.CollectAndCompare(c, strings.NewReader(expected), "filebeat_registry_file_lag_bytes"); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestRegistryMissingCheckpoint(t *testing.T) {
+	// active.dat still references the checkpoint filebeat has just replaced, log.json was truncated with it
+	dir := writeRegistry(t, map[string]string{
+		"active.dat": "/var/lib/filebeat/registry/filebeat/5.json",
+		"6.json":     `[{"_key":"filebeat::logs::native::1-1","source":"/var/log/a.log","offset":10},{"_key":"filebeat::logs::native::2-1","source":"/var/log/b.log","offset":20}]`,
+		"log.json":   "",
+	})
+	defer os.RemoveAll(dir)
+
+	c := NewRegistryCollector(&BeatInfo{Beat: "filebeat"}, NewRegistryReader(dir), 0, nil)
+
+	expected := `
+# HELP filebeat_registry_entries number of entries in the registry
+# TYPE filebeat_registry_entries gauge
+filebeat_registry_entries 2
+`
+
+	if err := testutil.CollectAndCompare(c, strings.NewReader(expected), "filebeat_registry_entries"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.Remove(filepath.Join(dir, "registry", "filebeat", "6.json")); err != nil {
+		t.Fatal(err)
+	}
+
+	// without any checkpoint left the scrape is skipped rather than reporting an empty registry
+	ch := make(chan prometheus.Metric, 16)
+	c.Collect(ch)
+	close(ch)
+
+	if n := len(ch); n != 0 {
+		t.Errorf("got %d metrics without a checkpoint, want none", n)
+	}
+}
+
+func TestRegistryCheckpointAge(t *testing.T) {
+	dir := writeRegistry(t, map[string]string{"log.json": ""})
+	defer os.RemoveAll(dir)
+
+	c := NewRegistryCollector(&BeatInfo{Beat: "filebeat"}, NewRegistryReader(dir), 0, nil)
+
+	ch := make(chan prometheus.Metric, 16)
+	c.Collect(ch)
+	close(ch)
+
+	for metric := range ch {
+		if strings.Contains(metric.Desc().String(), `"filebeat_registry_checkpoint_age_seconds"`) {
+			t.Error("checkpoint age exported for a registry without a checkpoint")
+		}
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "registry", "filebeat", "1.json"), []byte("[]"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ch = make(chan prometheus.Metric, 16)
+	c.Collect(ch)
+	close(ch)
+
+	found := false
+	for metric := range ch {
+		if strings.Contains(metric.Desc().String(), `"filebeat_registry_checkpoint_age_seconds"`) {
+			found = true
+		}
+	}
+
+	if !found {
+		t.Error("checkpoint age not exported once the registry has a checkpoint")
+	}
+}
+
+func TestRegistryReaderSharesState(t *testing.T) {
+	dir := writeRegistry(t, map[string]string{
+		"1.json":   `[{"_key":"filebeat::logs::native::1-1","source":"/var/log/a.log","offset":10}]`,
+		"log.json": "",
+	})
+	defer os.RemoveAll(dir)
+
+	reader := NewRegistryReader(dir)
+
+	first, err := reader.read()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the unharvested collector reads the registry on the same scrape
+	second, err := reader.read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first != second {
+		t.Error("unchanged registry was parsed again")
+	}
+
+	registryLog := "{\"op\":\"set\",\"id\":2}\n{\"k\":\"filebeat::logs::native::2-1\",\"v\":{\"source\":\"/var/log/b.log\",\"offset\":0}}\n"
+	if err := ioutil.WriteFile(filepath.Join(dir, "registry", "filebeat", "log.json"), []byte(registryLog), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	third, err := reader.read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(third.Entries) != 2 {
+		t.Errorf("got %d entries after the log changed, want 2", len(third.Entries))
+	}
+}
@@ -1,6 +1,10 @@
 package collector
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
+
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -13,6 +17,23 @@ type BeatInfo struct {
 	Version  string `json:"version"`
 }
 
+// versionNumbers returns the major and minor version of the beat
+func (b *BeatInfo) versionNumbers() (major int, minor int, err error) {
+	parts := strings.SplitN(b.Version, ".", 3)
+	if len(parts) < 2 {
+		return 0, 0, fmt.Errorf("version %q has no minor version", b.Version)
+	}
+
+	if major, err = strconv.Atoi(parts[0]); err != nil {
+		return 0, 0, err
+	}
+	if minor, err = strconv.Atoi(parts[1]); err != nil {
+		return 0, 0, err
+	}
+
+	return major, minor, nil
+}
+
 //Stats stats endpoint json structure
 type Stats struct {
 	System     System      `json:"system"`
@@ -23,6 +44,26 @@ type Stats struct {
 	Metricbeat Metricbeat  `json:"metricbeat"`
 	Auditd     AuditdStats `json:"auditd"`
 	Apmserver  Apmserver   `json:"apm-server"`
+	Heartbeat  Heartbeat   `json:"heartbeat"`
+
+	Journalbeat  Journalbeat  `json:"journalbeat"`
+	Functionbeat Functionbeat `json:"functionbeat"`
+	Osquerybeat  Osquerybeat  `json:"osquerybeat"`
+
+	// fleet-server sections are not nested under a common key
+	FleetServer
+
+	// apm-server 8.x elasticsearch output
+	Output ApmserverOutput `json:"output"`
+
+	// auditbeat file_integrity scanner
+	FileIntegrity FileIntegrity `json:"file_integrity"`
+
+	// packetbeat sections are not nested under a common key
+	Packetbeat
+
+	// logstash /_node/stats
+	Logstash
 }
 
 type exportedMetrics []struct {
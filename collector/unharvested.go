@@ -0,0 +1,218 @@
+package collector
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+	"github.com/trustpilot/beat-exporter/internal/beatconfig"
+)
+
+// recursiveGlobDepth is how many directory levels filebeat expands a ** path component to
+const recursiveGlobDepth = 8
+
+// harvestGlob is a path glob of an enabled filebeat input together with its exclusions
+type harvestGlob struct {
+	pattern  string
+	patterns []string
+	exclude  []*regexp.Regexp
+}
+
+type unharvestedCollector struct {
+	beatInfo    *BeatInfo
+	registry    *RegistryReader
+	globs       []harvestGlob
+	matched     *prometheus.Desc
+	unharvested *prometheus.Desc
+	oldestAge   *prometheus.Desc
+}
+
+// NewUnharvestedCollector constructor
+func NewUnharvestedCollector(beatInfo *BeatInfo, registry *RegistryReader, inputs []beatconfig.Input) prometheus.Collector {
+	var (
+		globs []harvestGlob
+		seen  = make(map[string]bool)
+	)
+
+	for _, input := range inputs {
+		if !input.Enabled {
+			continue
+		}
+
+		var exclude []*regexp.Regexp
+		for _, expr := range input.ExcludeFiles {
+			re, err := regexp.Compile(expr)
+			if err != nil {
+				log.Errorf("Ignoring invalid exclude_files pattern %q: %v", expr, err)
+				continue
+			}
+			exclude = append(exclude, re)
+		}
+
+		for _, pattern := range input.Paths {
+			if seen[pattern] {
+				continue
+			}
+			seen[pattern] = true
+
+			patterns, err := globPatterns(pattern, input.RecursiveGlob)
+			if err != nil {
+				log.Errorf("Ignoring input path glob: %v", err)
+				continue
+			}
+
+			globs = append(globs, harvestGlob{pattern: pattern, patterns: patterns, exclude: exclude})
+		}
+	}
+
+	return &unharvestedCollector{
+		beatInfo: beatInfo,
+		registry: registry,
+		globs:    globs,
+		matched: prometheus.NewDesc(
+			prometheus.BuildFQName(beatInfo.Beat, "input_glob", "matched_files"),
+			"files matching an input path glob",
+			[]string{"glob"}, nil,
+		),
+		unharvested: prometheus.NewDesc(
+			prometheus.BuildFQName(beatInfo.Beat, "input_glob", "unharvested_files"),
+			"files matching an input path glob without a registry entry",
+			[]string{"glob"}, nil,
+		),
+		oldestAge: prometheus.NewDesc(
+			prometheus.BuildFQName(beatInfo.Beat, "input_glob", "unharvested_oldest_file_age_seconds"),
+			"time since the oldest unharvested file was modified",
+			[]string{"glob"}, nil,
+		),
+	}
+}
+
+// Describe returns all descriptions of the collector.
+func (c *unharvestedCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.matched
+	ch <- c.unharvested
+	ch <- c.oldestAge
+}
+
+// Collect returns the current state of all metrics of the collector.
+func (c *unharvestedCollector) Collect(ch chan<- prometheus.Metric) {
+
+	state, err := c.registry.read()
+	if err != nil {
+		log.Errorf("Could not read filebeat registry: %v", err)
+		return
+	}
+
+	harvested := make(map[string]bool, len(state.Entries))
+	for _, entry := range state.Entries {
+		harvested[entry.Source] = true
+	}
+
+	now := time.Now()
+
+	for _, glob := range c.globs {
+		var files []string
+
+		for _, pattern := range glob.patterns {
+			matches, err := filepath.Glob(pattern)
+			if err != nil {
+				log.Errorf("Invalid input path glob %q: %v", glob.pattern, err)
+				break
+			}
+			files = append(files, matches...)
+		}
+
+		var (
+			matched, unharvested float64
+			oldest               time.Time
+		)
+
+		for _, file := range files {
+			if glob.excluded(file) {
+				continue
+			}
+
+			fi, err := os.Stat(file)
+			if err != nil || !fi.Mode().IsRegular() {
+				continue
+			}
+
+			matched++
+
+			if harvested[file] {
+				continue
+			}
+
+			unharvested++
+
+			if oldest.IsZero() || fi.ModTime().Before(oldest) {
+				oldest = fi.ModTime()
+			}
+		}
+
+		var age float64
+		if !oldest.IsZero() {
+			age = now.Sub(oldest).Seconds()
+		}
+
+		ch <- prometheus.MustNewConstMetric(c.matched, prometheus.GaugeValue, matched, glob.pattern)
+		ch <- prometheus.MustNewConstMetric(c.unharvested, prometheus.GaugeValue, unharvested, glob.pattern)
+		ch <- prometheus.MustNewConstMetric(c.oldestAge, prometheus.GaugeValue, age, glob.pattern)
+	}
+
+}
+
+func (g harvestGlob) excluded(file string) bool {
+	for _, re := range g.exclude {
+		if re.MatchString(file) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// globPatterns expands a ** path component to zero up to recursiveGlobDepth directory levels
+// like filebeat's recursive_glob, the expanded patterns never match the same file twice
+func globPatterns(pattern string, recursive bool) ([]string, error) {
+	if !recursive {
+		return []string{pattern}, nil
+	}
+
+	sep := string(filepath.Separator)
+	components := strings.Split(filepath.FromSlash(pattern), sep)
+
+	doublestar := -1
+	for i, component := range components {
+		if component != "**" {
+			continue
+		}
+		if doublestar >= 0 {
+			return nil, fmt.Errorf("multiple ** in %q", pattern)
+		}
+		doublestar = i
+	}
+
+	if doublestar < 0 {
+		return []string{pattern}, nil
+	}
+
+	var patterns []string
+
+	for depth := 0; depth <= recursiveGlobDepth; depth++ {
+		expanded := append([]string{}, components[:doublestar]...)
+		for i := 0; i < depth; i++ {
+			expanded = append(expanded, "*")
+		}
+		expanded = append(expanded, components[doublestar+1:]...)
+
+		patterns = append(patterns, strings.Join(expanded, sep))
+	}
+
+	return patterns, nil
+}
@@ -0,0 +1,78 @@
+package collector
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/trustpilot/beat-exporter/internal/beatconfig"
+)
+
+func TestUnharvestedRecursiveGlob(t *testing.T) {
+	dir, err := ioutil.TempDir("", "unharvested")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	logs := filepath.Join(dir, "logs")
+	for _, file := range []string{"app.log", "nginx/access.log", "nginx/vhosts/shop.log"} {
+		path := filepath.Join(logs, filepath.FromSlash(file))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte("line\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	registryDir := filepath.Join(dir, "data", "registry", "filebeat")
+	if err := os.MkdirAll(registryDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	registryLog := fmt.Sprintf("{\"op\":\"set\",\"id\":1}\n{\"k\":\"filebeat::logs::native::1-2\",\"v\":{\"source\":%q,\"offset\":5}}\n",
+		filepath.Join(logs, "app.log"))
+	if err := ioutil.WriteFile(filepath.Join(registryDir, "log.json"), []byte(registryLog), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	pattern := filepath.Join(logs, "**", "*.log")
+
+	tests := []struct {
+		name                 string
+		recursive            bool
+		matched, unharvested int
+	}{
+		{"recursive", true, 3, 2},
+		{"recursive_glob disabled", false, 1, 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := NewUnharvestedCollector(&BeatInfo{Beat: "filebeat"}, NewRegistryReader(filepath.Join(dir, "data")), []beatconfig.Input{
+				{Type: "log", Enabled: true, Paths: []string{pattern}, RecursiveGlob: test.recursive},
+			})
+
+			expected := fmt.Sprintf(`
+# HELP filebeat_input_glob_matched_files files matching an input path glob
+# TYPE filebeat_input_glob_matched_files gauge
+filebeat_input_glob_matched_files{glob=%[1]q} %[2]d
+# HELP filebeat_input_glob_unharvested_files files matching an input path glob without a registry entry
+# TYPE filebeat_input_glob_unharvested_files gauge
+filebeat_input_glob_unharvested_files{glob=%[1]q} %[3]d
+`, pattern, test.matched, test.unharvested)
+
+			if err := testutil.CollectAndCompare(c, strings.NewReader(expected),
+				"filebeat_input_glob_matched_files",
+				"filebeat_input_glob_unharvested_files",
+			); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
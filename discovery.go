@@ -0,0 +1,68 @@
+package main
+
+import (
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+	"github.com/trustpilot/beat-exporter/collector"
+	"github.com/trustpilot/beat-exporter/internal/discovery"
+)
+
+// discoverBeats returns a scan for every beat running on the host, each labelled with its monitoring endpoint as target
+func discoverBeats(name string, timeout time.Duration, options collector.Options) func() ([]scrapeTarget, error) {
+	return func() ([]scrapeTarget, error) {
+		beats, err := discovery.Beats()
+		if err != nil {
+			return nil, err
+		}
+
+		targets := make([]scrapeTarget, 0, len(beats))
+
+		for _, beat := range beats {
+			beat := beat
+
+			targets = append(targets, scrapeTarget{
+				key:      beat.URI,
+				revision: strconv.Itoa(beat.PID),
+				labels:   prometheus.Labels{"target": beat.URI},
+				newCollector: func() (prometheus.Collector, error) {
+					return newDiscoveredCollector(beat, name, timeout, options)
+				},
+			})
+		}
+
+		return targets, nil
+	}
+}
+
+func newDiscoveredCollector(beat discovery.Beat, name string, timeout time.Duration, options collector.Options) (prometheus.Collector, error) {
+	beatURL, err := url.Parse(beat.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	httpClient, unixPath := newBeatClient(beatURL, timeout)
+
+	beatInfo, err := loadBeatType(httpClient, *beatURL)
+	if err != nil {
+		return nil, err
+	}
+
+	options.PID = beat.PID
+	options.SocketPath = unixPath
+
+	options.FilebeatInputs, err = beat.Config.FilebeatInputs()
+	if err != nil {
+		log.Errorf("Could not load filebeat inputs of %s: %v", beat.URI, err)
+	}
+
+	// beat.data-path only enables the data path collectors, each beat uses its own path.data
+	if options.DataPath != "" {
+		options.DataPath = beat.DataPath
+	}
+
+	return collector.NewMainCollector(httpClient, beatURL, name, beatInfo, options), nil
+}
@@ -6,6 +6,8 @@ require (
 	github.com/konsorten/go-windows-terminal-sequences v1.0.2 // indirect
 	github.com/prometheus/client_golang v1.3.0
 	github.com/prometheus/common v0.8.0
+	github.com/prometheus/procfs v0.0.8
 	github.com/sirupsen/logrus v1.4.2
 	golang.org/x/sys v0.0.0-20200113162924-86b910548bc1
+	gopkg.in/yaml.v2 v2.2.4
 )
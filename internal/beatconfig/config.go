@@ -0,0 +1,363 @@
+package beatconfig
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// maxExpandDepth limits how deep references to other settings are followed, guarding against cycles
+const maxExpandDepth = 10
+
+// referencePattern matches ${name} and ${name:default} references in values, and $${...} which escapes them
+var referencePattern = regexp.MustCompile(`\$?\$\{([^}:]+)(?::([^}]*))?\}`)
+
+// Config is a parsed beat configuration file
+type Config struct {
+	Path string
+	data map[string]interface{}
+}
+
+// Input is a filebeat input of the configuration
+type Input struct {
+	Type          string
+	Enabled       bool
+	Paths         []string
+	ExcludeFiles  []string
+	RecursiveGlob bool
+}
+
+// Load reads and parses a beat configuration file
+func Load(path string) (*Config, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw map[interface{}]interface{}
+
+	if err := yaml.Unmarshal(content, &raw); err != nil {
+		return nil, fmt.Errorf("could not parse %s: %v", path, err)
+	}
+
+	return &Config{
+		Path: path,
+		data: normalize(raw).(map[string]interface{}),
+	}, nil
+}
+
+// normalize converts yaml maps to string keyed maps and expands dotted keys,
+// beats treat `http.port: 5066` and `http: {port: 5066}` the same way
+func normalize(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{})
+		for key, val := range v {
+			setPath(m, strings.Split(fmt.Sprint(key), "."), normalize(val))
+		}
+		return m
+	case []interface{}:
+		for i := range v {
+			v[i] = normalize(v[i])
+		}
+		return v
+	case nil:
+		return map[string]interface{}{}
+	default:
+		return v
+	}
+}
+
+func setPath(m map[string]interface{}, path []string, value interface{}) {
+	if len(path) == 1 {
+		// merge with keys set through a dotted path
+		if existing, ok := m[path[0]].(map[string]interface{}); ok {
+			if nested, ok := value.(map[string]interface{}); ok {
+				for k, v := range nested {
+					setPath(existing, []string{k}, v)
+				}
+				return
+			}
+		}
+		m[path[0]] = value
+		return
+	}
+
+	nested, ok := m[path[0]].(map[string]interface{})
+	if !ok {
+		nested = make(map[string]interface{})
+		m[path[0]] = nested
+	}
+
+	setPath(nested, path[1:], value)
+}
+
+// Set overrides the value at a dotted path the way `-E key=value` does, the value is parsed as yaml
+func (c *Config) Set(path string, value string) {
+	var parsed interface{}
+
+	if err := yaml.Unmarshal([]byte(value), &parsed); err != nil || parsed == nil {
+		parsed = value
+	}
+
+	setPath(c.data, strings.Split(path, "."), normalize(parsed))
+}
+
+// Get returns the value at a dotted path with ${name} references resolved
+func (c *Config) Get(path string) (interface{}, bool) {
+	value, ok := c.lookup(path)
+	if !ok {
+		return nil, false
+	}
+
+	return c.expand(value, 0), true
+}
+
+// lookup returns the value at a dotted path as it is written in the file
+func (c *Config) lookup(path string) (interface{}, bool) {
+	var value interface{} = c.data
+
+	for _, key := range strings.Split(path, ".") {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+
+		value, ok = m[key]
+		if !ok {
+			return nil, false
+		}
+	}
+
+	return value, true
+}
+
+// expand resolves references in string values against other settings first and the environment second,
+// like beats do. A reference without a default that cannot be resolved expands to an empty string.
+func (c *Config) expand(value interface{}, depth int) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, val := range v {
+			m[key] = c.expand(val, depth)
+		}
+		return m
+	case []interface{}:
+		l := make([]interface{}, len(v))
+		for i := range v {
+			l[i] = c.expand(v[i], depth)
+		}
+		return l
+	case string:
+		if depth > maxExpandDepth || !strings.Contains(v, "${") {
+			return v
+		}
+
+		expanded := referencePattern.ReplaceAllStringFunc(v, func(ref string) string {
+			if strings.HasPrefix(ref, "$$") {
+				return ref[1:]
+			}
+
+			match := referencePattern.FindStringSubmatch(ref)
+			if resolved, ok := c.resolve(match[1], depth); ok {
+				return resolved
+			}
+			return match[2]
+		})
+
+		return parseScalar(expanded)
+	default:
+		return v
+	}
+}
+
+func (c *Config) resolve(name string, depth int) (string, bool) {
+	if value, ok := c.lookup(name); ok {
+		return fmt.Sprint(c.expand(value, depth+1)), true
+	}
+
+	if name == "path.config" {
+		return c.configDir(), true
+	}
+
+	return os.LookupEnv(name)
+}
+
+// configDir returns path.config, which defaults to the directory of the configuration file
+func (c *Config) configDir() string {
+	return c.String("path.config", filepath.Dir(c.Path))
+}
+
+// parseScalar gives an expanded value its yaml type, so `port: ${PORT}` still is a number
+func parseScalar(value string) interface{} {
+	var parsed interface{}
+
+	if err := yaml.Unmarshal([]byte(value), &parsed); err != nil || parsed == nil {
+		return value
+	}
+
+	switch parsed.(type) {
+	case map[interface{}]interface{}, []interface{}:
+		return value
+	default:
+		return parsed
+	}
+}
+
+// String returns the value at a dotted path as string, or def if it is not set
+func (c *Config) String(path string, def string) string {
+	value, ok := c.Get(path)
+	if !ok {
+		return def
+	}
+
+	return fmt.Sprint(value)
+}
+
+// Bool returns the value at a dotted path as bool, or def if it is not set or no boolean
+func (c *Config) Bool(path string, def bool) bool {
+	value, ok := c.Get(path)
+	if !ok {
+		return def
+	}
+
+	if b, ok := boolValue(value); ok {
+		return b
+	}
+
+	return def
+}
+
+// boolValue accepts booleans and, like beats, strings such as "true"
+func boolValue(value interface{}) (bool, bool) {
+	switch v := value.(type) {
+	case bool:
+		return v, true
+	case string:
+		b, err := strconv.ParseBool(v)
+		return b, err == nil
+	default:
+		return false, false
+	}
+}
+
+// HTTPURI returns the address of the beat's monitoring endpoint
+func (c *Config) HTTPURI() (string, error) {
+	if !c.Bool("http.enabled", false) {
+		return "", fmt.Errorf("http.enabled is not set to true in %s", c.Path)
+	}
+
+	host := c.String("http.host", "localhost")
+	if strings.HasPrefix(host, "unix://") {
+		return host, nil
+	}
+
+	return "http://" + net.JoinHostPort(host, c.String("http.port", "5066")), nil
+}
+
+// FilebeatInputs returns the inputs configured under filebeat.inputs and in the
+// external input files matched by filebeat.config.inputs.path
+func (c *Config) FilebeatInputs() ([]Input, error) {
+	value, _ := c.Get("filebeat.inputs")
+	inputs := parseInputs(value)
+
+	pattern := c.String("filebeat.config.inputs.path", "")
+	if pattern == "" || !c.Bool("filebeat.config.inputs.enabled", true) {
+		return inputs, nil
+	}
+
+	if !filepath.IsAbs(pattern) {
+		pattern = filepath.Join(c.configDir(), pattern)
+	}
+
+	files, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filebeat.config.inputs.path %q: %v", pattern, err)
+	}
+
+	for _, file := range files {
+		content, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		// external input files hold a plain list of inputs
+		var list []interface{}
+
+		if err := yaml.Unmarshal(content, &list); err != nil {
+			return nil, fmt.Errorf("could not parse %s: %v", file, err)
+		}
+
+		inputs = append(inputs, parseInputs(c.expand(normalize(list), 0))...)
+	}
+
+	return inputs, nil
+}
+
+func parseInputs(value interface{}) []Input {
+	list, _ := value.([]interface{})
+
+	var inputs []Input
+
+	for _, item := range list {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		input := Input{
+			Type:          fmt.Sprint(m["type"]),
+			Enabled:       true,
+			Paths:         stringList(m["paths"]),
+			ExcludeFiles:  stringList(m["exclude_files"]),
+			RecursiveGlob: true,
+		}
+
+		if enabled, ok := boolValue(m["enabled"]); ok {
+			input.Enabled = enabled
+		}
+
+		// the log input sets recursive_glob.enabled, filestream prospector.scanner.recursive_glob
+		for _, path := range [][]string{{"recursive_glob", "enabled"}, {"prospector", "scanner", "recursive_glob"}} {
+			if recursive, ok := boolValue(nestedValue(m, path)); ok {
+				input.RecursiveGlob = recursive
+			}
+		}
+
+		inputs = append(inputs, input)
+	}
+
+	return inputs
+}
+
+func nestedValue(m map[string]interface{}, path []string) interface{} {
+	var value interface{} = m
+
+	for _, key := range path {
+		nested, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = nested[key]
+	}
+
+	return value
+}
+
+func stringList(value interface{}) []string {
+	list, _ := value.([]interface{})
+
+	var values []string
+	for _, v := range list {
+		values = append(values, fmt.Sprint(v))
+	}
+
+	return values
+}
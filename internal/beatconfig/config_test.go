@@ -0,0 +1,142 @@
+package beatconfig
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// loadYAML writes content to a temporary beat configuration file and loads it
+func loadYAML(t *testing.T, content string) *Config {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "beatconfig")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(dir, "filebeat.yml")
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+
+	config, err := Load(path)
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+
+	return config
+}
+
+func TestExpand(t *testing.T) {
+	os.Setenv("BEATCONFIG_TEST_PORT", "5067")
+	defer os.Unsetenv("BEATCONFIG_TEST_PORT")
+	os.Unsetenv("BEATCONFIG_TEST_UNSET")
+
+	config := loadYAML(t, `
+logs: /var/log
+http.port: ${BEATCONFIG_TEST_PORT:5066}
+http.host: ${BEATCONFIG_TEST_UNSET:localhost}
+unset: ${BEATCONFIG_TEST_UNSET}
+path: ${logs}/app
+escaped: $${logs} is ${logs}
+`)
+	defer os.RemoveAll(filepath.Dir(config.Path))
+
+	tests := []struct {
+		path     string
+		expected interface{}
+	}{
+		{"http.port", 5067},
+		{"http.host", "localhost"},
+		{"unset", ""},
+		{"path", "/var/log/app"},
+		{"escaped", "${logs} is /var/log"},
+	}
+
+	for _, test := range tests {
+		value, ok := config.Get(test.path)
+		if !ok {
+			t.Errorf("%s is not set", test.path)
+			continue
+		}
+		if value != test.expected {
+			t.Errorf("%s: got %#v, want %#v", test.path, value, test.expected)
+		}
+	}
+}
+
+func TestHTTPURI(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{"defaults", "http.enabled: true", "http://localhost:5066"},
+		{"quoted boolean", "http:\n  enabled: \"true\"\n  host: 10.0.0.1\n  port: 5067", "http://10.0.0.1:5067"},
+		{"unix socket", "http.enabled: true\nhttp.host: unix:///run/filebeat.sock", "unix:///run/filebeat.sock"},
+		{"disabled", "http.enabled: \"false\"", ""},
+		{"not set", "http.port: 5067", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := loadYAML(t, test.content)
+			defer os.RemoveAll(filepath.Dir(config.Path))
+
+			uri, err := config.HTTPURI()
+			if test.expected == "" {
+				if err == nil {
+					t.Fatalf("expected an error, got %q", uri)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if uri != test.expected {
+				t.Errorf("got %q, want %q", uri, test.expected)
+			}
+		})
+	}
+}
+
+func TestFilebeatInputs(t *testing.T) {
+	config := loadYAML(t, `
+filebeat.inputs:
+  - type: log
+    paths: [/var/log/*.log]
+    exclude_files: ['\.gz$']
+  - type: filestream
+    enabled: "false"
+    paths: [/var/log/nginx/*.log]
+  - type: filestream
+    paths: [/srv/**/*.log]
+    prospector.scanner.recursive_glob: false
+  - type: log
+    paths: [/opt/**/*.log]
+    recursive_glob:
+      enabled: "false"
+`)
+	defer os.RemoveAll(filepath.Dir(config.Path))
+
+	inputs, err := config.FilebeatInputs()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Input{
+		{Type: "log", Enabled: true, Paths: []string{"/var/log/*.log"}, ExcludeFiles: []string{`\.gz$`}, RecursiveGlob: true},
+		{Type: "filestream", Enabled: false, Paths: []string{"/var/log/nginx/*.log"}, RecursiveGlob: true},
+		{Type: "filestream", Enabled: true, Paths: []string{"/srv/**/*.log"}, RecursiveGlob: false},
+		{Type: "log", Enabled: true, Paths: []string{"/opt/**/*.log"}, RecursiveGlob: false},
+	}
+
+	if !reflect.DeepEqual(inputs, expected) {
+		t.Errorf("got %+v, want %+v", inputs, expected)
+	}
+}
@@ -0,0 +1,147 @@
+package discovery
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/trustpilot/beat-exporter/internal/beatconfig"
+)
+
+// KnownBeats are the process names recognised as beats
+var KnownBeats = []string{
+	"apm-server",
+	"auditbeat",
+	"filebeat",
+	"fleet-server",
+	"functionbeat",
+	"heartbeat",
+	"journalbeat",
+	"metricbeat",
+	"osquerybeat",
+	"packetbeat",
+}
+
+// Beat is a beat process running on the host
+type Beat struct {
+	PID      int
+	Name     string
+	URI      string
+	DataPath string
+	Config   *beatconfig.Config
+}
+
+// beatArgs are the command line flags of a beat that affect where its configuration lives
+type beatArgs struct {
+	config     string
+	pathHome   string
+	pathConfig string
+	pathData   string
+	overrides  map[string]string
+}
+
+func isKnownBeat(name string) bool {
+	for _, beat := range KnownBeats {
+		if beat == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseArgs picks the flags out of a beat command line, accepting both `-c file` and `-c=file` forms
+func parseArgs(cmdline []string) beatArgs {
+	args := beatArgs{overrides: make(map[string]string)}
+
+	for i := 0; i < len(cmdline); i++ {
+		if !strings.HasPrefix(cmdline[i], "-") {
+			continue
+		}
+
+		name := strings.TrimLeft(cmdline[i], "-")
+		value, inline := "", false
+		if parts := strings.SplitN(name, "=", 2); len(parts) == 2 {
+			name, value, inline = parts[0], parts[1], true
+		}
+
+		switch name {
+		case "c", "path.home", "path.config", "path.data", "E":
+		default:
+			// other flags are not needed, their values are skipped as non flag arguments
+			continue
+		}
+
+		if !inline && i+1 < len(cmdline) {
+			i++
+			value = cmdline[i]
+		}
+
+		switch name {
+		case "c":
+			args.config = value
+		case "path.home":
+			args.pathHome = value
+		case "path.config":
+			args.pathConfig = value
+		case "path.data":
+			args.pathData = value
+		case "E":
+			if kv := strings.SplitN(value, "=", 2); len(kv) == 2 {
+				args.overrides[kv[0]] = kv[1]
+			}
+		}
+	}
+
+	return args
+}
+
+// load reads the configuration of a beat the same way the beat resolves it, relative paths are
+// relative to path.config which defaults to path.home, the directory of the binary
+func (a beatArgs) load(name string, executable string) (*beatconfig.Config, string, error) {
+	home := a.pathHome
+	if home == "" {
+		home = filepath.Dir(executable)
+	}
+
+	configDir := a.pathConfig
+	if configDir == "" {
+		configDir = home
+	}
+
+	configFile := a.config
+	if configFile == "" {
+		configFile = name + ".yml"
+	}
+	if !filepath.IsAbs(configFile) {
+		configFile = filepath.Join(configDir, configFile)
+	}
+
+	config, err := beatconfig.Load(configFile)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// command line paths take precedence over the file, ${path.config} references resolve against them
+	setDefault(config, "path.home", home, a.pathHome != "")
+	setDefault(config, "path.config", configDir, a.pathConfig != "")
+
+	for key, value := range a.overrides {
+		config.Set(key, value)
+	}
+
+	dataPath := a.pathData
+	if dataPath == "" {
+		dataPath = config.String("path.data", filepath.Join(config.String("path.home", home), "data"))
+	}
+
+	return config, dataPath, nil
+}
+
+// setDefault sets a setting unless the configuration file already has it, or always when forced
+func setDefault(config *beatconfig.Config, path string, value string, force bool) {
+	if _, ok := config.Get(path); ok && !force {
+		return
+	}
+
+	config.Set(path, value)
+}
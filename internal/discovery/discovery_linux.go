@@ -0,0 +1,66 @@
+//go:build linux
+// +build linux
+
+package discovery
+
+import (
+	"github.com/prometheus/procfs"
+	log "github.com/sirupsen/logrus"
+)
+
+// Beats scans /proc for running beats and resolves their monitoring endpoint from their configuration
+func Beats() ([]Beat, error) {
+	procs, err := procfs.AllProcs()
+	if err != nil {
+		return nil, err
+	}
+
+	var beats []Beat
+
+	for _, proc := range procs {
+		comm, err := proc.Comm()
+		if err != nil || !isKnownBeat(comm) {
+			continue
+		}
+
+		cmdline, err := proc.CmdLine()
+		if err != nil {
+			continue
+		}
+
+		args := parseArgs(cmdline)
+
+		// the executable only locates path.home when it is not given, reading /proc/<pid>/exe
+		// of another user's process needs root
+		var executable string
+		if args.pathHome == "" {
+			executable, err = proc.Executable()
+			if err != nil {
+				log.Warnf("Skipping %s (pid %d), could not resolve its executable to find path.home: %v", comm, proc.PID, err)
+				continue
+			}
+		}
+
+		config, dataPath, err := args.load(comm, executable)
+		if err != nil {
+			log.Errorf("Could not load configuration of %s (pid %d): %v", comm, proc.PID, err)
+			continue
+		}
+
+		uri, err := config.HTTPURI()
+		if err != nil {
+			log.Debugf("Skipping %s (pid %d): %v", comm, proc.PID, err)
+			continue
+		}
+
+		beats = append(beats, Beat{
+			PID:      proc.PID,
+			Name:     comm,
+			URI:      uri,
+			DataPath: dataPath,
+			Config:   config,
+		})
+	}
+
+	return beats, nil
+}
@@ -0,0 +1,13 @@
+//go:build !linux
+// +build !linux
+
+package discovery
+
+import (
+	"errors"
+)
+
+// Beats is only implemented on linux, where running processes can be read from /proc
+func Beats() ([]Beat, error) {
+	return nil, errors.New("beat discovery is only supported on linux")
+}
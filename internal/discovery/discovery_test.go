@@ -0,0 +1,91 @@
+package discovery
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		cmdline  []string
+		expected beatArgs
+	}{
+		{
+			name:    "separate values",
+			cmdline: []string{"/usr/share/filebeat/bin/filebeat", "-c", "/etc/filebeat/filebeat.yml", "--path.home", "/usr/share/filebeat", "--path.data", "/var/lib/filebeat"},
+			expected: beatArgs{
+				config:    "/etc/filebeat/filebeat.yml",
+				pathHome:  "/usr/share/filebeat",
+				pathData:  "/var/lib/filebeat",
+				overrides: map[string]string{},
+			},
+		},
+		{
+			name:    "inline values and overrides",
+			cmdline: []string{"metricbeat", "-c=metricbeat.yml", "-path.config=/etc/metricbeat", "-E", "http.enabled=true", "-E=http.port=5067"},
+			expected: beatArgs{
+				config:     "metricbeat.yml",
+				pathConfig: "/etc/metricbeat",
+				overrides:  map[string]string{"http.enabled": "true", "http.port": "5067"},
+			},
+		},
+		{
+			name:    "other flags",
+			cmdline: []string{"filebeat", "-e", "--strict.perms=false", "run", "-c", "filebeat.yml"},
+			expected: beatArgs{
+				config:    "filebeat.yml",
+				overrides: map[string]string{},
+			},
+		},
+		{
+			name:    "missing value",
+			cmdline: []string{"filebeat", "-c"},
+			expected: beatArgs{
+				overrides: map[string]string{},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if args := parseArgs(test.cmdline); !reflect.DeepEqual(args, test.expected) {
+				t.Errorf("got %+v, want %+v", args, test.expected)
+			}
+		})
+	}
+}
+
+func TestLoadWithoutExecutable(t *testing.T) {
+	home, err := ioutil.TempDir("", "discovery")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(home)
+
+	configDir := filepath.Join(home, "etc")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(configDir, "filebeat.yml"), []byte("http.enabled: true\nhttp.port: 5067\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// packaged beats pass their paths, the executable is not needed then
+	args := parseArgs([]string{"filebeat", "--path.home", home, "--path.config", configDir, "-c", filepath.Join(configDir, "filebeat.yml")})
+
+	config, dataPath, err := args.load("filebeat", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if uri, err := config.HTTPURI(); err != nil || uri != "http://localhost:5067" {
+		t.Errorf("got uri %q (%v), want http://localhost:5067", uri, err)
+	}
+	if want := filepath.Join(home, "data"); dataPath != want {
+		t.Errorf("got data path %q, want %q", dataPath, want)
+	}
+}
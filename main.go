@@ -19,6 +19,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/prometheus/common/version"
 	"github.com/trustpilot/beat-exporter/collector"
+	"github.com/trustpilot/beat-exporter/internal/beatconfig"
 	"github.com/trustpilot/beat-exporter/internal/service"
 )
 
@@ -33,10 +34,22 @@ func main() {
 		tlsCertFile   = flag.String("tls.certfile", "", "TLS certs file if you want to use tls instead of http")
 		tlsKeyFile    = flag.String("tls.keyfile", "", "TLS key file if you want to use tls instead of http")
 		metricsPath   = flag.String("web.telemetry-path", "/metrics", "Path under which to expose metrics.")
-		beatURI       = flag.String("beat.uri", "http://localhost:5066", "HTTP API address of beat.")
+		beatURI       = flag.String("beat.uri", "http://localhost:5066", "HTTP API address of beat, derived from beat.config when not set. unix-glob:// scrapes every socket matching the glob.")
 		beatTimeout   = flag.Duration("beat.timeout", 10*time.Second, "Timeout for trying to get stats from beat.")
 		showVersion   = flag.Bool("version", false, "Show version and exit")
 		systemBeat    = flag.Bool("beat.system", false, "Expose system stats")
+		dataPath      = flag.String("beat.data-path", "", "Path to the beat data directory, enables disk queue and filebeat registry inspection")
+		registryFiles = flag.Int("registry.max-files", 100, "Maximum number of files exported from the filebeat registry, the rest is summed up as path=\"other\"")
+		registryGroup = flag.String("registry.path-groups", "", "Comma separated glob patterns, files matching a pattern are exported as one path")
+		deletedFiles  = flag.Bool("beat.deleted-files", false, "Expose deleted files still held open by the beat (linux only)")
+		processStats  = flag.Bool("beat.process", false, "Expose process stats of the beat read from /proc (linux only)")
+		pidFile       = flag.String("beat.pidfile", "", "Pidfile of the beat, used to find the beat process")
+		processName   = flag.String("beat.process-name", "", "Process name of the beat, used to find the beat process when neither a pidfile nor a unix socket is given (default beat type)")
+		beatConfig    = flag.String("beat.config", "", "Path to the beat configuration file")
+		unharvested   = flag.Bool("filebeat.unharvested", false, "Expose files matching filebeat input paths that are not in the registry, requires beat.data-path and, unless discovering, beat.config")
+		discover      = flag.Bool("discovery", false, "Discover and scrape all beats running on the host instead of beat.uri (linux only)")
+		discoverEvery = flag.Duration("discovery.interval", time.Minute, "Interval between scans for running beats, agent components or sockets.")
+		agent         = flag.Bool("beat.agent", false, "Treat beat.uri as Elastic Agent monitoring endpoint and scrape all of its components")
 	)
 	flag.Parse()
 
@@ -53,32 +66,37 @@ func main() {
 		},
 	})
 
-	beatURL, err := url.Parse(*beatURI)
+	var (
+		config *beatconfig.Config
+		err    error
+	)
 
-	if err != nil {
-		log.Fatalf("failed to parse beat.uri, error: %v", err)
-	}
+	if *beatConfig != "" {
+		config, err = beatconfig.Load(*beatConfig)
+		if err != nil {
+			log.Fatalf("failed to load beat.config, error: %v", err)
+		}
 
-	httpClient := &http.Client{
-		Timeout: *beatTimeout,
+		if !flagSet("beat.uri") && !*discover {
+			*beatURI, err = config.HTTPURI()
+			if err != nil {
+				log.Fatalf("failed to derive beat.uri from beat.config, error: %v", err)
+			}
+		}
 	}
 
-	if beatURL.Scheme == "unix" {
-		unixPath := beatURL.Path
-		beatURL.Scheme = "http"
-		beatURL.Host = "localhost"
-		beatURL.Path = ""
-		httpClient.Transport = &http.Transport{
-			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
-				return (&net.Dialer{}).DialContext(ctx, "unix", unixPath)
-			},
+	if *unharvested {
+		switch {
+		case *agent || strings.HasPrefix(*beatURI, unixGlobScheme):
+			log.Fatal("filebeat.unharvested is not supported for agent components and unix-glob targets")
+		case *dataPath == "":
+			log.Fatal("filebeat.unharvested requires beat.data-path")
+		case config == nil && !*discover:
+			// discovered beats are read from their own configuration
+			log.Fatal("filebeat.unharvested requires beat.config")
 		}
 	}
 
-	log.Info("Exploring target for beat type")
-
-	var beatInfo *collector.BeatInfo
-
 	stopCh := make(chan bool)
 
 	err = service.SetupServiceListener(stopCh, serviceName, log.StandardLogger())
@@ -88,33 +106,114 @@ func main() {
 		}).Errorf("could not setup service listener: %v", err)
 	}
 
-	t := time.NewTicker(1 * time.Second)
-
-beatdiscovery:
-	for {
-		select {
-		case <-t.C:
-			beatInfo, err = loadBeatType(httpClient, *beatURL)
-			if err != nil {
-				log.Errorf("Could not load beat type, with error: %v, retrying in 1s", err)
-				continue
-			}
-
-			break beatdiscovery
+	var pathGroups []string
+	if *registryGroup != "" {
+		pathGroups = strings.Split(*registryGroup, ",")
+	}
 
-		case <-stopCh:
-			os.Exit(0) // signal received, stop gracefully
+	var filebeatInputs []beatconfig.Input
+	if config != nil {
+		filebeatInputs, err = config.FilebeatInputs()
+		if err != nil {
+			log.Fatalf("failed to load filebeat inputs of beat.config, error: %v", err)
 		}
 	}
 
-	t.Stop()
+	options := collector.Options{
+		SystemBeat:         *systemBeat,
+		DataPath:           *dataPath,
+		RegistryMaxFiles:   *registryFiles,
+		RegistryPathGroups: pathGroups,
+		DeletedFiles:       *deletedFiles,
+		Process:            *processStats,
+		PIDFile:            *pidFile,
+		ProcessName:        *processName,
+		Unharvested:        *unharvested,
+		FilebeatInputs:     filebeatInputs,
+	}
 
 	// version metric
 	registry := prometheus.NewRegistry()
 	versionMetric := version.NewCollector(Name)
-	mainCollector := collector.NewMainCollector(httpClient, beatURL, Name, beatInfo, *systemBeat)
 	registry.MustRegister(versionMetric)
-	registry.MustRegister(mainCollector)
+
+	switch {
+	case *discover:
+		targets := newTargetSet(registry, discoverBeats(Name, *beatTimeout, options))
+		go targets.run(*discoverEvery)
+
+		log.WithFields(log.Fields{
+			"addr": *listenAddress,
+		}).Infof("Starting exporter discovering local beats every %v", *discoverEvery)
+
+	case strings.HasPrefix(*beatURI, unixGlobScheme):
+		pattern := strings.TrimPrefix(*beatURI, unixGlobScheme)
+
+		targets := newTargetSet(registry, globSockets(pattern, Name, *beatTimeout, options))
+		go targets.run(*discoverEvery)
+
+		log.WithFields(log.Fields{
+			"addr": *listenAddress,
+		}).Infof("Starting exporter scraping sockets matching %s every %v", pattern, *discoverEvery)
+
+	case *agent:
+		agentURL, err := url.Parse(*beatURI)
+		if err != nil {
+			log.Fatalf("failed to parse beat.uri, error: %v", err)
+		}
+
+		httpClient, _ := newBeatClient(agentURL, *beatTimeout)
+
+		targets := newTargetSet(registry, agentComponents(httpClient, *agentURL, Name, options))
+		go targets.run(*discoverEvery)
+
+		log.WithFields(log.Fields{
+			"addr": *listenAddress,
+		}).Infof("Starting exporter scraping agent components every %v", *discoverEvery)
+
+	default:
+		beatURL, err := url.Parse(*beatURI)
+
+		if err != nil {
+			log.Fatalf("failed to parse beat.uri, error: %v", err)
+		}
+
+		httpClient, unixPath := newBeatClient(beatURL, *beatTimeout)
+
+		log.Info("Exploring target for beat type")
+
+		var beatInfo *collector.BeatInfo
+
+		t := time.NewTicker(1 * time.Second)
+
+	beatdiscovery:
+		for {
+			select {
+			case <-t.C:
+				beatInfo, err = loadBeatType(httpClient, *beatURL)
+				if err != nil {
+					log.Errorf("Could not load beat type, with error: %v, retrying in 1s", err)
+					continue
+				}
+
+				break beatdiscovery
+
+			case <-stopCh:
+				os.Exit(0) // signal received, stop gracefully
+			}
+		}
+
+		t.Stop()
+
+		options.SocketPath = unixPath
+
+		mainCollector := collector.NewMainCollector(httpClient, beatURL, Name, beatInfo, options)
+		registry.MustRegister(mainCollector)
+
+		log.WithFields(log.Fields{
+			"addr": *listenAddress,
+		}).Infof("Starting exporter with configured type: %s", beatInfo.Beat)
+	}
 
 	http.Handle(*metricsPath, promhttp.HandlerFor(
 		registry,
@@ -126,10 +225,6 @@ beatdiscovery:
 
 	http.HandleFunc("/", IndexHandler(*metricsPath))
 
-	log.WithFields(log.Fields{
-		"addr": *listenAddress,
-	}).Infof("Starting exporter with configured type: %s", beatInfo.Beat)
-
 	go func() {
 		defer func() {
 			stopCh <- true
@@ -187,6 +282,43 @@ func IndexHandler(metricsPath string) http.HandlerFunc {
 	}
 }
 
+// flagSet reports whether a flag was given on the command line
+func flagSet(name string) bool {
+	set := false
+
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+
+	return set
+}
+
+// newBeatClient returns a http client for the beat, unix:// addresses are rewritten to http://localhost
+// and dialed through the socket, whose path is returned
+func newBeatClient(beatURL *url.URL, timeout time.Duration) (*http.Client, string) {
+	httpClient := &http.Client{
+		Timeout: timeout,
+	}
+
+	var unixPath string
+
+	if beatURL.Scheme == "unix" {
+		unixPath = beatURL.Path
+		beatURL.Scheme = "http"
+		beatURL.Host = "localhost"
+		beatURL.Path = ""
+		httpClient.Transport = &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return (&net.Dialer{}).DialContext(ctx, "unix", unixPath)
+			},
+		}
+	}
+
+	return httpClient, unixPath
+}
+
 func loadBeatType(client *http.Client, url url.URL) (*collector.BeatInfo, error) {
 	beatInfo := &collector.BeatInfo{}
 
@@ -197,8 +329,7 @@ func loadBeatType(client *http.Client, url url.URL) (*collector.BeatInfo, error)
 	defer response.Body.Close()
 
 	if response.StatusCode != http.StatusOK {
-		log.Errorf("Beat URL: %q status code: %d", url.String(), response.StatusCode)
-		return beatInfo, err
+		return beatInfo, fmt.Errorf("beat URL %q status code: %d", url.String(), response.StatusCode)
 	}
 
 	bodyBytes, err := ioutil.ReadAll(response.Body)
@@ -213,6 +344,21 @@ func loadBeatType(client *http.Client, url url.URL) (*collector.BeatInfo, error)
 		return beatInfo, err
 	}
 
+	// Logstash answers with its node info, which has no beat field
+	if beatInfo.Beat == "" {
+		var node struct {
+			ID          string `json:"id"`
+			Host        string `json:"host"`
+			HTTPAddress string `json:"http_address"`
+		}
+
+		if err := json.Unmarshal(bodyBytes, &node); err == nil && node.HTTPAddress != "" {
+			beatInfo.Beat = "logstash"
+			beatInfo.Hostname = node.Host
+			beatInfo.UUID = node.ID
+		}
+	}
+
 	// Remove '-' from beatname
 	beatInfo.Beat = strings.ReplaceAll(beatInfo.Beat, "-", "")
 
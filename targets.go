@@ -0,0 +1,104 @@
+package main
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+)
+
+// scrapeTarget is a beat endpoint found by a scan, identified by key and labelled with labels.
+// A changed revision, like a new pid after a restart, recreates its collector.
+type scrapeTarget struct {
+	key          string
+	revision     string
+	labels       prometheus.Labels
+	newCollector func() (prometheus.Collector, error)
+}
+
+// registeredTarget is a target whose collector is registered
+type registeredTarget struct {
+	revision  string
+	labels    prometheus.Labels
+	collector prometheus.Collector
+}
+
+// targetSet keeps a collector registered for every target returned by scan
+type targetSet struct {
+	registry *prometheus.Registry
+	scan     func() ([]scrapeTarget, error)
+	targets  map[string]registeredTarget
+}
+
+func newTargetSet(registry *prometheus.Registry, scan func() ([]scrapeTarget, error)) *targetSet {
+	return &targetSet{
+		registry: registry,
+		scan:     scan,
+		targets:  make(map[string]registeredTarget),
+	}
+}
+
+func (s *targetSet) run(interval time.Duration) {
+	s.refresh()
+
+	for range time.Tick(interval) {
+		s.refresh()
+	}
+}
+
+func (s *targetSet) refresh() {
+	targets, err := s.scan()
+	if err != nil {
+		log.Errorf("Could not scan for targets: %v", err)
+		return
+	}
+
+	found := make(map[string]bool)
+
+	for _, target := range targets {
+		found[target.key] = true
+
+		// a restarted beat may have been upgraded, its collector is created again
+		if registered, ok := s.targets[target.key]; ok {
+			if registered.revision == target.revision {
+				continue
+			}
+			s.unregister(target.key)
+		}
+
+		c, err := target.newCollector()
+		if err != nil {
+			log.Errorf("Could not load beat type of %s, with error: %v", target.key, err)
+			// retried on the next refresh
+			continue
+		}
+
+		if err := prometheus.WrapRegistererWith(target.labels, s.registry).Register(c); err != nil {
+			log.Errorf("Could not register collector for %s: %v", target.key, err)
+			continue
+		}
+
+		s.targets[target.key] = registeredTarget{revision: target.revision, labels: target.labels, collector: c}
+
+		log.WithFields(log.Fields{
+			"target":   target.key,
+			"revision": target.revision,
+		}).Info("Found target")
+	}
+
+	for key := range s.targets {
+		if !found[key] {
+			log.WithFields(log.Fields{
+				"target": key,
+			}).Info("Target is gone")
+			s.unregister(key)
+		}
+	}
+}
+
+func (s *targetSet) unregister(key string) {
+	target := s.targets[key]
+
+	prometheus.WrapRegistererWith(target.labels, s.registry).Unregister(target.collector)
+	delete(s.targets, key)
+}
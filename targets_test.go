@@ -0,0 +1,99 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// testTarget returns a target whose collector exports a single gauge, counting how often it is created
+func testTarget(key string, revision string, created map[string]int, fail bool) scrapeTarget {
+	return scrapeTarget{
+		key:      key,
+		revision: revision,
+		labels:   prometheus.Labels{"target": key},
+		newCollector: func() (prometheus.Collector, error) {
+			if fail {
+				return nil, errors.New("beat not ready")
+			}
+			created[key]++
+
+			return prometheus.NewGauge(prometheus.GaugeOpts{Name: "test_target_up", Help: "test target"}), nil
+		},
+	}
+}
+
+// gatheredTargets returns the target labels of the registered collectors
+func gatheredTargets(t *testing.T, registry *prometheus.Registry) []string {
+	t.Helper()
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var targets []string
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "target" {
+					targets = append(targets, label.GetValue())
+				}
+			}
+		}
+	}
+	sort.Strings(targets)
+
+	return targets
+}
+
+func TestTargetSetRefresh(t *testing.T) {
+	var (
+		registry = prometheus.NewRegistry()
+		created  = make(map[string]int)
+		scanned  []scrapeTarget
+	)
+
+	set := newTargetSet(registry, func() ([]scrapeTarget, error) { return scanned, nil })
+
+	steps := []struct {
+		name    string
+		targets []scrapeTarget
+		want    []string
+		created map[string]int
+	}{
+		{
+			name:    "new targets",
+			targets: []scrapeTarget{testTarget("a", "1", created, false), testTarget("b", "1", created, false), testTarget("c", "1", created, true)},
+			want:    []string{"a", "b"},
+			created: map[string]int{"a": 1, "b": 1},
+		},
+		{
+			name:    "restarted target and failed target retried",
+			targets: []scrapeTarget{testTarget("a", "1", created, false), testTarget("b", "2", created, false), testTarget("c", "1", created, false)},
+			want:    []string{"a", "b", "c"},
+			created: map[string]int{"a": 1, "b": 2, "c": 1},
+		},
+		{
+			name:    "gone targets",
+			targets: []scrapeTarget{testTarget("b", "2", created, false)},
+			want:    []string{"b"},
+			created: map[string]int{"a": 1, "b": 2, "c": 1},
+		},
+	}
+
+	for _, step := range steps {
+		scanned = step.targets
+		set.refresh()
+
+		if got := gatheredTargets(t, registry); !reflect.DeepEqual(got, step.want) {
+			t.Errorf("%s: got targets %v, want %v", step.name, got, step.want)
+		}
+		if !reflect.DeepEqual(created, step.created) {
+			t.Errorf("%s: got collectors created %v, want %v", step.name, created, step.created)
+		}
+	}
+}
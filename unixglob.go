@@ -0,0 +1,61 @@
+package main
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/trustpilot/beat-exporter/collector"
+)
+
+// unixGlobScheme is the beat.uri scheme scraping every unix socket matching a glob
+const unixGlobScheme = "unix-glob://"
+
+// globSockets returns a scan for the beat monitoring sockets matching pattern, each labelled with its socket path
+func globSockets(pattern string, name string, timeout time.Duration, options collector.Options) func() ([]scrapeTarget, error) {
+	return func() ([]scrapeTarget, error) {
+		paths, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, err
+		}
+
+		targets := make([]scrapeTarget, 0, len(paths))
+
+		for _, path := range paths {
+			fi, err := os.Stat(path)
+			if err != nil || fi.Mode()&os.ModeSocket == 0 {
+				continue
+			}
+
+			socketURL := &url.URL{Scheme: "unix", Path: path}
+
+			targets = append(targets, scrapeTarget{
+				key: path,
+				// a restarted beat creates its socket again
+				revision: fi.ModTime().String(),
+				labels:   prometheus.Labels{"socket": path},
+				newCollector: func() (prometheus.Collector, error) {
+					httpClient, unixPath := newBeatClient(socketURL, timeout)
+
+					beatInfo, err := loadBeatType(httpClient, *socketURL)
+					if err != nil {
+						return nil, err
+					}
+
+					options := options
+					options.SocketPath = unixPath
+					// the pid file, data path and inputs of the exporter flags belong to a single beat
+					options.PIDFile = ""
+					options.DataPath = ""
+					options.FilebeatInputs = nil
+
+					return collector.NewMainCollector(httpClient, socketURL, name, beatInfo, options), nil
+				},
+			})
+		}
+
+		return targets, nil
+	}
+}
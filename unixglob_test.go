@@ -0,0 +1,93 @@
+package main
+
+import (
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/trustpilot/beat-exporter/collector"
+)
+
+func TestGlobSockets(t *testing.T) {
+	dir, err := ioutil.TempDir("", "unixglob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	socket := filepath.Join(dir, "filebeat.sock")
+
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	go http.Serve(listener, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"beat": "filebeat", "version": "8.11.1"}`))
+	}))
+
+	// files that are no socket are skipped
+	if err := ioutil.WriteFile(filepath.Join(dir, "stale.sock"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	scan := globSockets(filepath.Join(dir, "*.sock"), "beat_exporter", time.Second, collector.Options{PIDFile: "/run/filebeat.pid"})
+
+	targets, err := scan()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(targets) != 1 {
+		t.Fatalf("got %d targets, want 1", len(targets))
+	}
+
+	target := targets[0]
+	if target.key != socket || target.labels["socket"] != socket || len(target.labels) != 1 {
+		t.Errorf("got target %s labelled %v, want %s labelled by its socket", target.key, target.labels, socket)
+	}
+	if target.revision == "" {
+		t.Error("socket target has no revision")
+	}
+
+	c, err := target.newCollector()
+	if err != nil {
+		t.Fatalf("could not create collector through the socket: %v", err)
+	}
+
+	registry := prometheus.NewRegistry()
+	if err := registry.Register(c); err != nil {
+		t.Fatal(err)
+	}
+
+	// a beat restarted in place creates its socket again
+	listener.Close()
+
+	restarted, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer restarted.Close()
+
+	if err := os.Chtimes(socket, time.Now().Add(time.Minute), time.Now().Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+
+	targets, err = scan()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(targets) != 1 || targets[0].revision == target.revision {
+		t.Errorf("recreated socket kept revision %q", target.revision)
+	}
+
+	if _, err := globSockets("[", "beat_exporter", time.Second, collector.Options{})(); err == nil {
+		t.Error("expected an error for an invalid glob")
+	}
+}